@@ -0,0 +1,266 @@
+package opensearchqueryreceiver
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// Aggregation shape identifiers, usable as QueryConfig.AggregationTypes
+// values to force a shape when auto-detection from the response body is
+// ambiguous (most notably "cardinality", which is otherwise indistinguishable
+// from any other single-value metric aggregation).
+const (
+	aggShapeStats          = "stats"
+	aggShapeExtendedStats  = "extended_stats"
+	aggShapePercentiles    = "percentiles"
+	aggShapePercentileRank = "percentile_ranks"
+	aggShapeCardinality    = "cardinality"
+	aggShapeTopHits        = "top_hits"
+	aggShapeDateHistogram  = "date_histogram"
+	aggShapeHistogram      = "histogram"
+	aggShapeFilters        = "filters"
+)
+
+// detectAggregationShape determines which known OpenSearch aggregation
+// response shape aggMap has, preferring an explicit QueryConfig.AggregationTypes
+// hint for aggName over auto-detection.
+func detectAggregationShape(aggName string, aggMap map[string]interface{}, queryConfig QueryConfig) string {
+	if hint, ok := queryConfig.AggregationTypes[aggName]; ok {
+		return hint
+	}
+
+	if _, ok := aggMap["values"].(map[string]interface{}); ok {
+		return aggShapePercentiles
+	}
+	if _, ok := aggMap["hits"].(map[string]interface{}); ok {
+		return aggShapeTopHits
+	}
+	if _, ok := aggMap["buckets"].(map[string]interface{}); ok {
+		return aggShapeFilters
+	}
+	if buckets, ok := aggMap["buckets"].([]interface{}); ok {
+		if bucketHasKeyAsString(buckets) {
+			return aggShapeDateHistogram
+		}
+		return aggShapeHistogram
+	}
+	if _, ok := aggMap["std_deviation"].(float64); ok {
+		return aggShapeExtendedStats
+	}
+	if _, avgOK := aggMap["avg"].(float64); avgOK {
+		if _, sumOK := aggMap["sum"].(float64); sumOK {
+			if _, countOK := aggMap["count"].(float64); countOK {
+				return aggShapeStats
+			}
+		}
+	}
+
+	return ""
+}
+
+func bucketHasKeyAsString(buckets []interface{}) bool {
+	for _, raw := range buckets {
+		if bucket, ok := raw.(map[string]interface{}); ok {
+			_, ok := bucket["key_as_string"].(string)
+			return ok
+		}
+	}
+	return false
+}
+
+// processStatsAggregation emits one gauge per stats/extended_stats field,
+// plus the std_deviation_bounds.upper/lower pair, sharing the base
+// aggregation name.
+func (s *scraper) processStatsAggregation(
+	sm pmetric.ScopeMetrics,
+	baseMetricName, aggName string,
+	aggMap map[string]interface{},
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	for _, field := range statsFields {
+		value, ok := aggMap[field].(float64)
+		if !ok {
+			continue
+		}
+		name := fmt.Sprintf("%s.agg.%s.%s", baseMetricName, aggName, field)
+		s.recordGaugeMetric(sm, name, fmt.Sprintf("%s aggregation %s", aggName, field), "", value, queryConfig, timestamp)
+	}
+
+	if bounds, ok := aggMap["std_deviation_bounds"].(map[string]interface{}); ok {
+		if upper, ok := bounds["upper"].(float64); ok {
+			name := fmt.Sprintf("%s.agg.%s.std_deviation_bounds.upper", baseMetricName, aggName)
+			s.recordGaugeMetric(sm, name, fmt.Sprintf("Upper standard deviation bound for %s", aggName), "", upper, queryConfig, timestamp)
+		}
+		if lower, ok := bounds["lower"].(float64); ok {
+			name := fmt.Sprintf("%s.agg.%s.std_deviation_bounds.lower", baseMetricName, aggName)
+			s.recordGaugeMetric(sm, name, fmt.Sprintf("Lower standard deviation bound for %s", aggName), "", lower, queryConfig, timestamp)
+		}
+	}
+}
+
+// processPercentilesAggregation emits one data point per percentile key,
+// with the percentile value carried as a "percentile" attribute.
+func (s *scraper) processPercentilesAggregation(
+	sm pmetric.ScopeMetrics,
+	baseMetricName, aggName string,
+	aggMap map[string]interface{},
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	values, ok := aggMap["values"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	name := fmt.Sprintf("%s.agg.%s", baseMetricName, aggName)
+	for percentile, raw := range values {
+		value, ok := raw.(float64)
+		if !ok {
+			continue
+		}
+		dp := s.newDataPoint(sm, name, fmt.Sprintf("Percentiles for %s", aggName), "", value, timestamp)
+		attrs := dp.Attributes()
+		attrs.PutStr("query.name", queryConfig.Name)
+		attrs.PutStr("percentile", percentile)
+		for k, v := range queryConfig.Labels {
+			attrs.PutStr(k, v)
+		}
+	}
+}
+
+// processCardinalityAggregation emits the distinct-value estimate as a gauge
+// with the OTel "{unique}" annotated unit.
+func (s *scraper) processCardinalityAggregation(
+	sm pmetric.ScopeMetrics,
+	baseMetricName, aggName string,
+	aggMap map[string]interface{},
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	value, ok := aggMap["value"].(float64)
+	if !ok {
+		return
+	}
+	name := fmt.Sprintf("%s.agg.%s", baseMetricName, aggName)
+	s.recordGaugeMetric(sm, name, fmt.Sprintf("Cardinality estimate for %s", aggName), "{unique}", value, queryConfig, timestamp)
+}
+
+// processTopHitsAggregation exposes hits.total.value as a metric. Forwarding
+// the inner hits themselves to the logs pipeline is left to a query that
+// targets the same documents directly with emit_logs, rather than threading
+// the logs scraper through the metrics path here.
+func (s *scraper) processTopHitsAggregation(
+	sm pmetric.ScopeMetrics,
+	baseMetricName, aggName string,
+	aggMap map[string]interface{},
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	hits, ok := aggMap["hits"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	total, ok := hits["total"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	value, ok := total["value"].(float64)
+	if !ok {
+		return
+	}
+	name := fmt.Sprintf("%s.agg.%s.hits.total", baseMetricName, aggName)
+	s.recordGaugeMetric(sm, name, fmt.Sprintf("Total hits for %s", aggName), "hits", value, queryConfig, timestamp)
+}
+
+// processFiltersAggregation emits one gauge per named bucket, tagged with
+// the filter name, for the "filters" aggregation's map-shaped buckets.
+func (s *scraper) processFiltersAggregation(
+	sm pmetric.ScopeMetrics,
+	baseMetricName, aggName string,
+	aggMap map[string]interface{},
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	buckets, ok := aggMap["buckets"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	name := fmt.Sprintf("%s.agg.%s.doc_count", baseMetricName, aggName)
+	for filterName, raw := range buckets {
+		bucket, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		docCount, ok := bucket["doc_count"].(float64)
+		if !ok {
+			continue
+		}
+		dp := s.newDataPoint(sm, name, fmt.Sprintf("Document count for %s filter", aggName), "documents", docCount, timestamp)
+		attrs := dp.Attributes()
+		attrs.PutStr("query.name", queryConfig.Name)
+		attrs.PutStr("filter", filterName)
+		for k, v := range queryConfig.Labels {
+			attrs.PutStr(k, v)
+		}
+	}
+}
+
+// processHistogramAggregation records each bucket's doc_count tagged with
+// its key (rendered via bucketKeyString, which prefers key_as_string when
+// present). For a date_histogram, the bucket's own time replaces the
+// collection timestamp on the emitted data point; a plain numeric histogram
+// has no time semantics to its key (e.g. a price bucket boundary), so the
+// collection timestamp is kept instead.
+func (s *scraper) processHistogramAggregation(
+	sm pmetric.ScopeMetrics,
+	shape string,
+	baseMetricName, aggName string,
+	aggMap map[string]interface{},
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	buckets, ok := aggMap["buckets"].([]interface{})
+	if !ok {
+		return
+	}
+
+	name := fmt.Sprintf("%s.agg.%s.bucket.doc_count", baseMetricName, aggName)
+	for _, raw := range buckets {
+		bucket, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		docCount, ok := bucket["doc_count"].(float64)
+		if !ok {
+			continue
+		}
+
+		bucketTimestamp := timestamp
+		if shape == aggShapeDateHistogram {
+			if keyMillis, ok := bucket["key"].(float64); ok {
+				bucketTimestamp = pcommon.NewTimestampFromTime(time.UnixMilli(int64(keyMillis)))
+			}
+		}
+
+		dp := s.newDataPoint(sm, name, fmt.Sprintf("Bucket document count for %s", aggName), "documents", docCount, bucketTimestamp)
+		attrs := dp.Attributes()
+		attrs.PutStr("query.name", queryConfig.Name)
+		attrs.PutStr("bucket.key", bucketKeyString(bucket))
+		attrs.PutStr("aggregation", aggName)
+		for k, v := range queryConfig.Labels {
+			attrs.PutStr(k, v)
+		}
+
+		for nestedAggName, nestedAggValue := range bucket {
+			if nestedAggName != "key" && nestedAggName != "doc_count" && nestedAggName != "key_as_string" {
+				nestedBaseMetric := fmt.Sprintf("%s.%s", baseMetricName, bucketKeyString(bucket))
+				s.processAggregation(sm, nestedBaseMetric, nestedAggName, nestedAggValue, queryConfig, timestamp)
+			}
+		}
+	}
+}
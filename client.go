@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
@@ -18,6 +20,15 @@ type OpenSearchClient struct {
 	config     *Config
 	httpClient *http.Client
 	logger     *zap.Logger
+
+	retryTransport *retryTransport
+
+	// nodePool and probeClient support multi-node deployments configured
+	// via Config.Endpoints. probeClient carries the same auth/TLS wrapping
+	// as httpClient but skips the node pool and retry layers, since health
+	// probes and sniffs target one specific node directly.
+	nodePool    *nodePool
+	probeClient *http.Client
 }
 
 // NewOpenSearchClient creates a new OpenSearch client
@@ -34,17 +45,114 @@ func NewOpenSearchClient(cfg *Config, logger *zap.Logger) (*OpenSearchClient, er
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
 
-	httpClient.Transport = &http.Transport{
+	var signedTransport http.RoundTripper = &http.Transport{
 		TLSClientConfig: tlsConfig,
 	}
 
+	// In oauth2 mode, wrap the transport so every request is transparently
+	// signed with a bearer token acquired via the client-credentials flow.
+	if cfg.Mode == "oauth2" {
+		signedTransport, err = newOAuth2Transport(context.Background(), *cfg.OAuth2, signedTransport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure oauth2 transport: %w", err)
+		}
+	}
+
+	// In aws_sigv4 mode, wrap the transport so every request is signed with
+	// AWS SigV4 using the default credentials chain.
+	if cfg.Mode == "aws_sigv4" {
+		signedTransport, err = newSigV4Transport(context.Background(), *cfg.SigV4, signedTransport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure sigv4 transport: %w", err)
+		}
+	}
+
+	// Wrap everything in the retry/circuit-breaker transport so a transient
+	// failure (network error or 429/502/503/504) doesn't drop the whole
+	// scrape cycle.
+	retryTransport := newRetryTransport(signedTransport, cfg.RetryOnFailure)
+
+	// Round-robin requests across every configured node (just Endpoint
+	// itself when Endpoints is unset), failing over to the next node on a
+	// connection error or 5xx instead of surfacing it to the scraper.
+	pool, err := newNodePool(cfg.GetEndpoints(), cfg.Sniff, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure node pool: %w", err)
+	}
+	httpClient.Transport = newNodePoolTransport(retryTransport, pool)
+
+	probeClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: signedTransport,
+	}
+
 	return &OpenSearchClient{
-		config:     cfg,
-		httpClient: httpClient,
-		logger:     logger,
+		config:         cfg,
+		httpClient:     httpClient,
+		logger:         logger,
+		retryTransport: retryTransport,
+		nodePool:       pool,
+		probeClient:    probeClient,
 	}, nil
 }
 
+// ConfigureTelemetry wires the client's retry transport to emit
+// otelcol_receiver_opensearchquery_retries_total and _breaker_state via
+// meter. Callers that don't need self-observability metrics can skip it.
+func (c *OpenSearchClient) ConfigureTelemetry(meter metric.Meter) error {
+	return c.retryTransport.configureTelemetry(meter)
+}
+
+// StartNodePool launches the client's background node-health probing and,
+// if Config.Sniff is set, periodic cluster discovery. It runs until ctx is
+// cancelled, so callers should invoke it once at startup (in its own
+// goroutine) alongside the scraper's collection loops.
+func (c *OpenSearchClient) StartNodePool(ctx context.Context) {
+	go c.nodePool.run(ctx, c.probeClient)
+}
+
+// ConfigureAuth wraps the client's transport with the collector's standard
+// client-auth extension (e.g. oauth2clientauthextension) named by
+// Config.ClientConfig.Auth, resolved from host's extensions. It's a no-op
+// when Auth isn't set, so existing direct/oauth2/aws_sigv4 configs are
+// unaffected.
+//
+// It must be called once at startup, after the node pool and retry
+// transports are already in place, since host (and the extensions it
+// exposes) is only available once the receiver's Start is invoked. The
+// resulting round tripper wraps everything else, so the auth header is
+// attached once per logical request regardless of which node it's ultimately
+// sent to or how many times the retry transport resends it.
+//
+// probeClient is wrapped the same way, over its own pre-pool/pre-retry
+// transport, so health probes and sniffs (which deliberately skip the node
+// pool and retry layers) still authenticate against a cluster that requires
+// the extension.
+func (c *OpenSearchClient) ConfigureAuth(host component.Host) error {
+	if c.config.ClientConfig.Auth == nil {
+		return nil
+	}
+
+	authClient, err := c.config.ClientConfig.Auth.GetClientAuthenticator(host.GetExtensions())
+	if err != nil {
+		return fmt.Errorf("failed to resolve auth extension %q: %w", c.config.ClientConfig.Auth.AuthenticatorID, err)
+	}
+
+	roundTripper, err := authClient.RoundTripper(c.httpClient.Transport)
+	if err != nil {
+		return fmt.Errorf("failed to build authenticated round tripper: %w", err)
+	}
+	c.httpClient.Transport = roundTripper
+
+	probeRoundTripper, err := authClient.RoundTripper(c.probeClient.Transport)
+	if err != nil {
+		return fmt.Errorf("failed to build authenticated probe round tripper: %w", err)
+	}
+	c.probeClient.Transport = probeRoundTripper
+
+	return nil
+}
+
 // SearchRequest represents the request payload for OpenSearch queries
 type SearchRequest struct {
 	Query map[string]interface{} `json:"query"`
@@ -88,19 +196,35 @@ type Hit struct {
 	ID     string                 `json:"_id"`
 	Score  *float64               `json:"_score"`
 	Source map[string]interface{} `json:"_source"`
+
+	// Sort carries the values of this hit's sort clause, used as the next
+	// page's search_after when paginating.
+	Sort []interface{} `json:"sort,omitempty"`
 }
 
 // ExecuteQuery executes a query against OpenSearch and returns the response
 func (c *OpenSearchClient) ExecuteQuery(ctx context.Context, query QueryConfig) (*SearchResponse, error) {
-	// Build the search request
+	return c.executeQuery(ctx, query, c.addTimeRangeFilter(query.Query))
+}
+
+// ExecuteQuerySince executes a query against OpenSearch with its time range
+// filter's lower bound pinned to since instead of derived from
+// LookbackPeriod, used by the logs pipeline to resume from a high-water mark.
+func (c *OpenSearchClient) ExecuteQuerySince(ctx context.Context, query QueryConfig, since time.Time) (*SearchResponse, error) {
+	return c.executeQuery(ctx, query, c.addTimeRangeFilterFrom(query.Query, since))
+}
+
+// executeQuery issues a _search request with an already time-filtered query.
+func (c *OpenSearchClient) executeQuery(ctx context.Context, query QueryConfig, filteredQuery map[string]interface{}) (*SearchResponse, error) {
+	// Build the search request. Aggs is attached as a top-level sibling of
+	// Query rather than merged into it, so it survives Query being wrapped
+	// in the time-range bool query above.
 	searchReq := SearchRequest{
-		Query: query.Query,
+		Query: filteredQuery,
 		Size:  10000, // Maximum results per query
+		Aggs:  query.Aggs,
 	}
 
-	// Add time range filter if needed
-	searchReq.Query = c.addTimeRangeFilter(searchReq.Query)
-
 	// Marshal request body
 	reqBody, err := json.Marshal(searchReq)
 	if err != nil {
@@ -170,14 +294,24 @@ func (c *OpenSearchClient) ExecuteQuery(ctx context.Context, query QueryConfig)
 // addTimeRangeFilter adds a time range filter to the query based on lookback period
 func (c *OpenSearchClient) addTimeRangeFilter(query map[string]interface{}) map[string]interface{} {
 	now := time.Now()
-	startTime := now.Add(-c.config.LookbackPeriod)
+	return c.addTimeRangeFilterFrom(query, now.Add(-c.config.LookbackPeriod))
+}
+
+// addTimeRangeFilterFrom adds a time range filter to the query with an
+// explicit lower bound, used when resuming from a high-water mark instead of
+// the static LookbackPeriod.
+func (c *OpenSearchClient) addTimeRangeFilterFrom(query map[string]interface{}, startTime time.Time) map[string]interface{} {
+	now := time.Now()
 
-	// Create time range filter
+	// Create time range filter. RFC3339Nano (not RFC3339) preserves
+	// sub-second precision so a startTime advanced by a single nanosecond
+	// past the last-seen document's timestamp actually excludes it,
+	// instead of rounding back to that same second and re-matching it.
 	timeFilter := map[string]interface{}{
 		"range": map[string]interface{}{
 			c.config.TimeField: map[string]interface{}{
-				"gte": startTime.Format(time.RFC3339),
-				"lte": now.Format(time.RFC3339),
+				"gte": startTime.Format(time.RFC3339Nano),
+				"lte": now.Format(time.RFC3339Nano),
 			},
 		},
 	}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
+
+	"github.com/opensearchqueryreceiver/internal/metadata"
 )
 
 // Config defines the configuration for the OpenSearch Query Receiver.
-// It supports two operational modes:
+// It supports four operational modes:
 //
 // 1. Direct Mode: Connects directly to OpenSearch with optional authentication
 //   - Use this for testing and development environments
@@ -18,6 +21,15 @@ import (
 // 2. Proxy Mode: Connects through an OAuth2 authentication proxy
 //   - Use this for production environments requiring OAuth2
 //   - The proxy handles token management and renewal
+//   - Deprecated in favor of setting ClientConfig.Auth to reference
+//     oauth2clientauthextension alongside Direct Mode (see ProxyEndpoint)
+//
+// 3. OAuth2 Mode: Connects directly to OpenSearch using an in-process
+//   - OAuth2 client-credentials flow (see OAuth2Config)
+//   - Use this instead of Proxy Mode to avoid running a separate proxy binary
+//
+// 4. AWS SigV4 Mode: Connects directly to AWS OpenSearch Service or
+//   - OpenSearch Serverless, signing every request with AWS SigV4 (see SigV4Config)
 type Config struct {
 	// ClientConfig configures the HTTP client (timeouts, TLS, headers, etc.)
 	confighttp.ClientConfig `mapstructure:",squash"`
@@ -28,9 +40,13 @@ type Config struct {
 	// InitialDelay is the delay before first collection
 	InitialDelay time.Duration `mapstructure:"initial_delay"`
 
-	// Mode specifies the operational mode: "direct" or "proxy"
+	// Mode specifies the operational mode: "direct", "proxy", "oauth2", or "aws_sigv4"
 	// - "direct": Connect directly to OpenSearch (uses Endpoint, Username, Password)
 	// - "proxy": Connect through OAuth2 proxy (uses ProxyEndpoint, requires proxy setup)
+	// - "oauth2": Connect directly to OpenSearch using an in-process OAuth2
+	//   client-credentials flow (uses OAuth2, requires no proxy)
+	// - "aws_sigv4": Connect directly to OpenSearch (AWS OpenSearch Service or
+	//   OpenSearch Serverless), signing every request with AWS SigV4 (uses SigV4)
 	Mode string `mapstructure:"mode"`
 
 	// Username for basic authentication (direct mode only)
@@ -41,12 +57,39 @@ type Config struct {
 
 	// ProxyEndpoint is the URL of the OAuth2 authentication proxy (proxy mode only)
 	// Example: http://localhost:8080
+	//
+	// Deprecated: proxy mode exists for users who already run a sidecar
+	// OAuth2 proxy. New configs should instead set mode: "direct" and
+	// reference the collector's oauth2clientauthextension via
+	// ClientConfig.Auth (the "auth" block), which fetches and refreshes
+	// tokens in-process and needs no separate proxy binary.
 	ProxyEndpoint string `mapstructure:"proxy_endpoint"`
 
-	// Queries is a list of OpenSearch queries to execute
+	// OAuth2 configures in-process OAuth2 client-credentials authentication
+	// (oauth2 mode only)
+	OAuth2 *OAuth2Config `mapstructure:"oauth2"`
+
+	// SigV4 configures AWS SigV4 request signing (aws_sigv4 mode only)
+	SigV4 *SigV4Config `mapstructure:"sigv4"`
+
+	// Queries is a list of OpenSearch queries to execute. When Targets is
+	// set, this also doubles as the shared query library that targets can
+	// reference by name via TargetConfig.QueryNames.
 	// Each query should have a name and a query body
 	Queries []QueryConfig `mapstructure:"queries"`
 
+	// Targets lets a single receiver instance fan out to multiple OpenSearch
+	// clusters concurrently, each with its own endpoint, mode, and
+	// credentials. When non-empty, Targets take over collection: each
+	// target's own endpoint, mode, and index_pattern replace the top-level
+	// Endpoint/Mode/IndexPattern fields above, which are not used as
+	// defaults, and Queries serves only as the shared query library.
+	Targets []TargetConfig `mapstructure:"targets"`
+
+	// MaxConcurrentTargets bounds how many targets are collected in
+	// parallel. Defaults to the number of targets, capped at 8.
+	MaxConcurrentTargets int `mapstructure:"max_concurrent_targets"`
+
 	// IndexPattern is the OpenSearch index pattern to query
 	// Example: "logs-*" or "metrics-2024.01.*"
 	IndexPattern string `mapstructure:"index_pattern"`
@@ -55,9 +98,187 @@ type Config struct {
 	// Default: "@timestamp"
 	TimeField string `mapstructure:"time_field"`
 
+	// FieldMappings translates PromQL/LogQL label names to the OpenSearch
+	// document field they're stored under, for queries with
+	// QueryConfig.Language "promql" or "logql" (e.g. "service" ->
+	// "service.keyword"). A label with no entry here is used as the field
+	// name verbatim.
+	FieldMappings map[string]string `mapstructure:"field_mappings"`
+
 	// LookbackPeriod defines how far back to query for data
 	// Default: 5m (5 minutes)
 	LookbackPeriod time.Duration `mapstructure:"lookback_period"`
+
+	// StorageID references an extension.StorageExtension (by component ID,
+	// e.g. "file_storage/opensearch") used to persist each emit_logs
+	// query's last-successful end_time, keyed by {receiver_id, query_name},
+	// so the logs pipeline resumes across restarts and collector reloads
+	// instead of always starting from now-LookbackPeriod. Unset disables
+	// persistence; the logs scraper falls back to its in-memory high-water
+	// mark only.
+	StorageID *component.ID `mapstructure:"storage"`
+
+	// MaxCatchup bounds how far back a resumed query is allowed to replay
+	// after an outage: on startup the logs scraper resumes from
+	// max(persisted end_time, now-MaxCatchup) rather than blindly
+	// replaying from the persisted end_time, however old. Defaults to
+	// LookbackPeriod if unset.
+	MaxCatchup time.Duration `mapstructure:"max_catchup"`
+
+	// RetryOnFailure configures the client's retry and circuit-breaker
+	// behavior, mirroring the collector's exporterhelper conventions.
+	RetryOnFailure RetryConfig `mapstructure:"retry_on_failure"`
+
+	// Endpoints lists every node of a multi-node OpenSearch cluster to
+	// round-robin requests across (direct, oauth2, and aws_sigv4 modes
+	// only). A node that errors or returns a 5xx is taken out of rotation
+	// and retried on the next healthy node; it's re-probed in the
+	// background and returned to rotation once healthy again. When empty,
+	// Endpoint is used as the sole node, so existing single-endpoint
+	// configs keep working unchanged.
+	Endpoints []string `mapstructure:"endpoints"`
+
+	// Sniff discovers the cluster's full node list on startup and
+	// periodically thereafter via GET /_nodes/http, merging newly found
+	// nodes into the client's node pool. Leave this false when Endpoint(s) point at a
+	// load balancer or proxy in front of the cluster, since sniffing would
+	// then discover nodes unreachable from outside it.
+	Sniff bool `mapstructure:"sniff"`
+
+	// MetricsBuilderConfig controls the enable state of the receiver's
+	// fixed-name metrics (opensearch.query.result_count/took/shards,
+	// opensearch.query.circuit_open, opensearch.query.pagination.pages_fetched/
+	// pit_open_duration) and resource attributes,
+	// generated via mdatagen from metadata.yaml. It does not cover
+	// aggregation results or QueryConfig.Metrics extractors, whose metric
+	// names are user-defined rather than part of the generated catalog.
+	MetricsBuilderConfig metadata.MetricsBuilderConfig `mapstructure:",squash"`
+}
+
+// TargetConfig defines one OpenSearch cluster to query within a multi-target
+// receiver (see Config.Targets). It mirrors the top-level connection and
+// auth fields on Config so a single receiver can run against clusters with
+// different endpoints, modes, and credentials.
+type TargetConfig struct {
+	// Name identifies this target and is attached to its metrics as the
+	// opensearch.cluster resource attribute.
+	Name string `mapstructure:"name"`
+
+	// ClientConfig configures the HTTP client for this target.
+	confighttp.ClientConfig `mapstructure:",squash"`
+
+	// Mode specifies this target's operational mode: "direct", "proxy", "oauth2", or "aws_sigv4".
+	Mode string `mapstructure:"mode"`
+
+	// Username for basic authentication (direct mode only)
+	Username string `mapstructure:"username"`
+
+	// Password for basic authentication (direct mode only)
+	Password string `mapstructure:"password"`
+
+	// ProxyEndpoint is the URL of the OAuth2 authentication proxy (proxy mode only)
+	ProxyEndpoint string `mapstructure:"proxy_endpoint"`
+
+	// OAuth2 configures in-process OAuth2 client-credentials authentication
+	// (oauth2 mode only)
+	OAuth2 *OAuth2Config `mapstructure:"oauth2"`
+
+	// SigV4 configures AWS SigV4 request signing (aws_sigv4 mode only)
+	SigV4 *SigV4Config `mapstructure:"sigv4"`
+
+	// IndexPattern is the OpenSearch index pattern to query on this target.
+	IndexPattern string `mapstructure:"index_pattern"`
+
+	// Queries overrides the shared query library with queries specific to
+	// this target. Mutually exclusive with QueryNames.
+	Queries []QueryConfig `mapstructure:"queries"`
+
+	// QueryNames selects a subset of the top-level Config.Queries library to
+	// run against this target, by QueryConfig.Name. Mutually exclusive with Queries.
+	QueryNames []string `mapstructure:"query_names"`
+
+	// RetryOnFailure configures this target's retry/circuit-breaker
+	// behavior. Defaults to the top-level Config.RetryOnFailure.
+	RetryOnFailure RetryConfig `mapstructure:"retry_on_failure"`
+}
+
+// resolveQueries returns the queries to run against this target, either its
+// own Queries, the subset of library named by QueryNames, or all of library
+// if neither is set.
+func (t *TargetConfig) resolveQueries(library []QueryConfig) ([]QueryConfig, error) {
+	if len(t.Queries) > 0 {
+		return t.Queries, nil
+	}
+	if len(t.QueryNames) == 0 {
+		return library, nil
+	}
+
+	byName := make(map[string]QueryConfig, len(library))
+	for _, q := range library {
+		byName[q.Name] = q
+	}
+
+	resolved := make([]QueryConfig, 0, len(t.QueryNames))
+	for _, name := range t.QueryNames {
+		q, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("query_names references unknown query %q", name)
+		}
+		resolved = append(resolved, q)
+	}
+	return resolved, nil
+}
+
+// RetryConfig configures retry-with-backoff and circuit-breaking for
+// requests to OpenSearch, so a transient cluster outage drops individual
+// scrapes instead of failing the collector.
+type RetryConfig struct {
+	// Enabled turns on retry/circuit-breaking. Defaults to true.
+	Enabled bool `mapstructure:"enabled"`
+
+	// InitialInterval is the base delay before the first retry. Defaults to 500ms.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+
+	// MaxInterval caps the exponential backoff delay. Defaults to 30s.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+
+	// MaxElapsedTime bounds the total time spent retrying a single request
+	// before giving up. Defaults to 1m when unset (0).
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+
+	// MaxRetries caps the number of retry attempts for a single request,
+	// independent of MaxElapsedTime. Defaults to 5.
+	MaxRetries int `mapstructure:"max_retries"`
+
+	// BreakerThreshold is the number of consecutive failures after which the
+	// circuit breaker opens and short-circuits further requests. Defaults to 5.
+	BreakerThreshold int `mapstructure:"breaker_threshold"`
+
+	// BreakerCooldown is how long the breaker stays open before allowing a
+	// half-open probe request through. Defaults to 30s.
+	BreakerCooldown time.Duration `mapstructure:"breaker_cooldown"`
+}
+
+// setDefaults fills in zero-valued fields with their documented defaults.
+func (r *RetryConfig) setDefaults() {
+	if r.InitialInterval == 0 {
+		r.InitialInterval = 500 * time.Millisecond
+	}
+	if r.MaxInterval == 0 {
+		r.MaxInterval = 30 * time.Second
+	}
+	if r.MaxElapsedTime == 0 {
+		r.MaxElapsedTime = time.Minute
+	}
+	if r.MaxRetries == 0 {
+		r.MaxRetries = 5
+	}
+	if r.BreakerThreshold == 0 {
+		r.BreakerThreshold = 5
+	}
+	if r.BreakerCooldown == 0 {
+		r.BreakerCooldown = 30 * time.Second
+	}
 }
 
 // QueryConfig defines a single query to execute against OpenSearch
@@ -68,41 +289,200 @@ type QueryConfig struct {
 	// Description provides context about what this query does
 	Description string `mapstructure:"description"`
 
+	// Alias identifies this query on the receiver.alias resource attribute
+	// attached to its metrics, so a fast error-count query and a slow
+	// capacity-planning query in the same receiver are distinguishable
+	// downstream. Defaults to Name when empty.
+	Alias string `mapstructure:"alias"`
+
+	// CollectionInterval, when set, overrides the receiver's global
+	// CollectionInterval for this query only, so it can run on its own
+	// cadence independent of the other configured queries.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// InitialDelay, when set, overrides the receiver's global InitialDelay
+	// for this query only.
+	InitialDelay time.Duration `mapstructure:"initial_delay"`
+
 	// Query is the OpenSearch query DSL in JSON format
 	// This will be sent as the request body to the _search endpoint
 	Query map[string]interface{} `mapstructure:"query"`
 
+	// Aggs is aggregation DSL to send as a top-level sibling of "query" in
+	// the search request body. It has no mapstructure tag because it isn't
+	// user-configurable: it's populated internally by executeAndRecordTranslatedQuery
+	// for translated (promql/logql) queries that need an aggregation, since
+	// embedding it inside Query would put it inside a query clause once the
+	// time-range filter wraps Query in a bool query.
+	Aggs map[string]interface{}
+
+	// Language selects the query interface used to execute this query:
+	// "dsl" (default) sends Query to _search, "sql" and "ppl" send
+	// QueryText to the OpenSearch SQL/PPL plugins, and "lucene", "promql",
+	// and "logql" send QueryText through the translator package, which
+	// compiles it to DSL (see Config.FieldMappings for label/field mapping).
+	Language string `mapstructure:"language"`
+
+	// QueryText is the raw SQL, PPL, Lucene, PromQL, or LogQL query string,
+	// used for every Language except "dsl", which uses Query instead.
+	QueryText string `mapstructure:"query_text"`
+
 	// MetricName is the name to use for the resulting metric
 	// If empty, defaults to "opensearch.query.{name}"
 	MetricName string `mapstructure:"metric_name"`
 
 	// Labels are additional key-value pairs to attach to the metric
 	Labels map[string]string `mapstructure:"labels"`
+
+	// Metrics declares one or more explicit metric extractors that walk this
+	// query's aggregation response and emit OTel metrics. If empty, the
+	// scraper falls back to its generic aggregation-to-metric conversion.
+	Metrics []MetricExtractorConfig `mapstructure:"metrics"`
+
+	// Pagination enables paginated execution for result sets larger than a
+	// single _search response (OpenSearch's 10,000-hit window). Leave unset
+	// to use the default single-request execution.
+	Pagination *PaginationConfig `mapstructure:"pagination"`
+
+	// EmitLogs, when true, additionally converts this query's hits into OTel
+	// log records instead of discarding them after the metrics above are derived.
+	EmitLogs bool `mapstructure:"emit_logs"`
+
+	// BodyField is the document field used as the log record body. If empty,
+	// the whole document is recorded as a structured body.
+	BodyField string `mapstructure:"body_field"`
+
+	// SeverityField is the document field promoted to the log record's severity text.
+	SeverityField string `mapstructure:"severity_field"`
+
+	// ServiceNameField is the document field promoted to a "service.name" log attribute.
+	ServiceNameField string `mapstructure:"service_name_field"`
+
+	// TraceIDField is the document field (hex-encoded) promoted to the log record's trace ID.
+	TraceIDField string `mapstructure:"trace_id_field"`
+
+	// SpanIDField is the document field (hex-encoded) promoted to the log record's span ID.
+	SpanIDField string `mapstructure:"span_id_field"`
+
+	// AggregationTypes optionally forces the response shape used to convert
+	// a named aggregation to metrics (e.g. "stats", "percentiles",
+	// "cardinality", "top_hits", "filters", "date_histogram"), keyed by
+	// aggregation name. Only needed when auto-detection from the response
+	// body is ambiguous, such as "cardinality" looking like any other
+	// single-value metric aggregation.
+	AggregationTypes map[string]string `mapstructure:"aggregation_types"`
+}
+
+// PaginationConfig configures paginated retrieval of large result sets via
+// point-in-time, search_after, or scroll.
+type PaginationConfig struct {
+	// Strategy selects the pagination mechanism: "search_after", "scroll", or "pit".
+	Strategy string `mapstructure:"strategy"`
+
+	// PageSize is the number of hits requested per page. Defaults to 1000.
+	PageSize int `mapstructure:"page_size"`
+
+	// MaxPages caps the number of pages fetched per collection cycle, to
+	// bound memory and collection time. 0 means unlimited.
+	MaxPages int `mapstructure:"max_pages"`
+
+	// MaxHits caps the total number of hits streamed per collection cycle,
+	// to bound memory. 0 means unlimited.
+	MaxHits int `mapstructure:"max_hits"`
+
+	// Sort is the sort clause used to page deterministically. Used by
+	// "search_after" and "pit"; defaults to
+	// [{"@timestamp": "asc"}, {"_shard_doc": "asc"}] if unset.
+	Sort []map[string]interface{} `mapstructure:"sort"`
+
+	// KeepAlive is how long OpenSearch should keep the PIT or scroll context
+	// alive between page requests. Defaults to 1m.
+	KeepAlive time.Duration `mapstructure:"keep_alive"`
+}
+
+// MetricExtractorConfig declares how to derive one or more OTel metrics from
+// an OpenSearch query response (hits or aggregations).
+type MetricExtractorConfig struct {
+	// Type selects the extraction strategy. One of: "hits_total",
+	// "terms_bucket", "stats", "extended_stats", "percentiles",
+	// "date_histogram", "scripted_metric".
+	Type string `mapstructure:"type"`
+
+	// Name is the metric name to emit (used as a base name for multi-value
+	// types like "stats" or "percentiles").
+	Name string `mapstructure:"name"`
+
+	// Path is the dot-separated path of aggregation names to descend into
+	// before applying the extractor, e.g. "by_region.avg_latency". Not used
+	// by "hits_total".
+	Path string `mapstructure:"path"`
+
+	// Value is a dot-separated pointer into the resolved aggregation object
+	// identifying the numeric field to record. Used by "scripted_metric" and,
+	// optionally, to pull a sub-metric out of each bucket for "terms_bucket"
+	// and "date_histogram" (defaults to "doc_count").
+	Value string `mapstructure:"value"`
+
+	// MetricType names the OTel metric shape ("gauge", "sum", or
+	// "histogram"). The extractors do not read it: every extractor emits
+	// gauge data points whatever this is set to.
+	MetricType string `mapstructure:"metric_type"`
+
+	// Unit is the OTel unit string attached to the emitted metric(s).
+	Unit string `mapstructure:"unit"`
+
+	// Attributes maps bucket fields (e.g. "key") to the attribute name they
+	// should be recorded under on each emitted data point. Only the "key"
+	// entry is used, and only by "terms_bucket" (which otherwise records the
+	// key as "bucket.key"); other entries and other extractor types ignore it.
+	Attributes map[string]string `mapstructure:"attributes"`
+}
+
+var validMetricExtractorTypes = map[string]bool{
+	"hits_total":      true,
+	"terms_bucket":    true,
+	"stats":           true,
+	"extended_stats":  true,
+	"percentiles":     true,
+	"date_histogram":  true,
+	"scripted_metric": true,
 }
 
 // Validate checks if the configuration is valid
 func (cfg *Config) Validate() error {
-	if cfg.Mode == "" {
-		return errors.New("mode must be specified (direct or proxy)")
+	if len(cfg.Targets) > 0 {
+		if err := cfg.validateTargets(); err != nil {
+			return err
+		}
+	} else {
+		if err := cfg.validateSingleCluster(); err != nil {
+			return err
+		}
 	}
 
-	if cfg.Mode != "direct" && cfg.Mode != "proxy" {
-		return fmt.Errorf("invalid mode '%s': must be 'direct' or 'proxy'", cfg.Mode)
+	// Set defaults
+	if cfg.TimeField == "" {
+		cfg.TimeField = "@timestamp"
 	}
 
-	// Validate direct mode configuration
-	if cfg.Mode == "direct" {
-		if cfg.Endpoint == "" {
-			return errors.New("endpoint must be specified in direct mode")
-		}
-		// Username and password are optional in direct mode (for unsecured instances)
+	if cfg.LookbackPeriod == 0 {
+		cfg.LookbackPeriod = 5 * time.Minute
 	}
 
-	// Validate proxy mode configuration
-	if cfg.Mode == "proxy" {
-		if cfg.ProxyEndpoint == "" {
-			return errors.New("proxy_endpoint must be specified in proxy mode")
-		}
+	if cfg.MaxCatchup == 0 {
+		cfg.MaxCatchup = cfg.LookbackPeriod
+	}
+
+	cfg.RetryOnFailure.setDefaults()
+
+	return nil
+}
+
+// validateSingleCluster validates the top-level connection and query
+// configuration used when Targets is not set.
+func (cfg *Config) validateSingleCluster() error {
+	if err := validateMode(cfg.Mode, cfg.Endpoint, cfg.Endpoints, cfg.ProxyEndpoint, cfg.OAuth2, cfg.SigV4); err != nil {
+		return err
 	}
 
 	// Validate queries
@@ -111,11 +491,8 @@ func (cfg *Config) Validate() error {
 	}
 
 	for i, query := range cfg.Queries {
-		if query.Name == "" {
-			return fmt.Errorf("query[%d]: name must be specified", i)
-		}
-		if query.Query == nil || len(query.Query) == 0 {
-			return fmt.Errorf("query[%d] (%s): query body must be specified", i, query.Name)
+		if err := validateQuery(i, query); err != nil {
+			return err
 		}
 	}
 
@@ -124,13 +501,166 @@ func (cfg *Config) Validate() error {
 		return errors.New("index_pattern must be specified")
 	}
 
-	// Set defaults
-	if cfg.TimeField == "" {
-		cfg.TimeField = "@timestamp"
+	return nil
+}
+
+// validateTargets validates Config.Targets and the shared query library they
+// may reference.
+func (cfg *Config) validateTargets() error {
+	for i, query := range cfg.Queries {
+		if err := validateQuery(i, query); err != nil {
+			return fmt.Errorf("queries library: %w", err)
+		}
 	}
 
-	if cfg.LookbackPeriod == 0 {
-		cfg.LookbackPeriod = 5 * time.Minute
+	for i, target := range cfg.Targets {
+		if target.Name == "" {
+			return fmt.Errorf("targets[%d]: name must be specified", i)
+		}
+		if err := validateMode(target.Mode, target.Endpoint, nil, target.ProxyEndpoint, target.OAuth2, target.SigV4); err != nil {
+			return fmt.Errorf("targets[%d] (%s): %w", i, target.Name, err)
+		}
+		if target.IndexPattern == "" {
+			return fmt.Errorf("targets[%d] (%s): index_pattern must be specified", i, target.Name)
+		}
+		if len(target.Queries) > 0 && len(target.QueryNames) > 0 {
+			return fmt.Errorf("targets[%d] (%s): queries and query_names are mutually exclusive", i, target.Name)
+		}
+
+		resolved, err := target.resolveQueries(cfg.Queries)
+		if err != nil {
+			return fmt.Errorf("targets[%d] (%s): %w", i, target.Name, err)
+		}
+		if len(resolved) == 0 {
+			return fmt.Errorf("targets[%d] (%s): at least one query must be configured or referenced", i, target.Name)
+		}
+		for j, query := range target.Queries {
+			if err := validateQuery(j, query); err != nil {
+				return fmt.Errorf("targets[%d] (%s): %w", i, target.Name, err)
+			}
+		}
+
+		target.RetryOnFailure.setDefaults()
+	}
+
+	if cfg.MaxConcurrentTargets < 0 {
+		return errors.New("max_concurrent_targets must not be negative")
+	}
+
+	return nil
+}
+
+// validateMode validates the auth mode shared by Config and TargetConfig.
+// endpoints is Config.Endpoints when validating the top-level config (nil
+// for TargetConfig, which doesn't support a node pool); either it or
+// endpoint alone satisfies the "an endpoint was configured" checks below.
+func validateMode(mode, endpoint string, endpoints []string, proxyEndpoint string, oauth2Cfg *OAuth2Config, sigV4Cfg *SigV4Config) error {
+	if mode == "" {
+		return errors.New("mode must be specified (direct, proxy, oauth2, or aws_sigv4)")
+	}
+	if mode != "direct" && mode != "proxy" && mode != "oauth2" && mode != "aws_sigv4" {
+		return fmt.Errorf("invalid mode '%s': must be 'direct', 'proxy', 'oauth2', or 'aws_sigv4'", mode)
+	}
+
+	hasEndpoint := endpoint != "" || len(endpoints) > 0
+	for _, e := range endpoints {
+		if e == "" {
+			return errors.New("endpoints must not contain empty entries")
+		}
+	}
+
+	switch mode {
+	case "direct":
+		if !hasEndpoint {
+			return errors.New("endpoint or endpoints must be specified in direct mode")
+		}
+		// Username and password are optional in direct mode (for unsecured instances)
+	case "proxy":
+		if proxyEndpoint == "" {
+			return errors.New("proxy_endpoint must be specified in proxy mode")
+		}
+	case "oauth2":
+		if !hasEndpoint {
+			return errors.New("endpoint or endpoints must be specified in oauth2 mode")
+		}
+		if oauth2Cfg == nil {
+			return errors.New("oauth2 block must be specified in oauth2 mode")
+		}
+		if err := oauth2Cfg.validate(); err != nil {
+			return fmt.Errorf("invalid oauth2 config: %w", err)
+		}
+	case "aws_sigv4":
+		if !hasEndpoint {
+			return errors.New("endpoint or endpoints must be specified in aws_sigv4 mode")
+		}
+		if sigV4Cfg == nil {
+			return errors.New("sigv4 block must be specified in aws_sigv4 mode")
+		}
+		if err := sigV4Cfg.validate(); err != nil {
+			return fmt.Errorf("invalid sigv4 config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateQuery validates a single QueryConfig, including its metric
+// extractors and pagination block.
+func validateQuery(i int, query QueryConfig) error {
+	if query.Name == "" {
+		return fmt.Errorf("query[%d]: name must be specified", i)
+	}
+	if query.CollectionInterval < 0 {
+		return fmt.Errorf("query[%d] (%s): collection_interval must not be negative", i, query.Name)
+	}
+	if query.InitialDelay < 0 {
+		return fmt.Errorf("query[%d] (%s): initial_delay must not be negative", i, query.Name)
+	}
+	switch query.Language {
+	case "", "dsl":
+		if len(query.Query) == 0 {
+			return fmt.Errorf("query[%d] (%s): query body must be specified", i, query.Name)
+		}
+	case "sql", "ppl", "lucene", "promql", "logql":
+		if query.QueryText == "" {
+			return fmt.Errorf("query[%d] (%s): query_text must be specified for language '%s'", i, query.Name, query.Language)
+		}
+	default:
+		return fmt.Errorf("query[%d] (%s): language must be 'dsl', 'sql', 'ppl', 'lucene', 'promql', or 'logql', got '%s'", i, query.Name, query.Language)
+	}
+
+	for j, extractor := range query.Metrics {
+		if !validMetricExtractorTypes[extractor.Type] {
+			return fmt.Errorf("query[%d] (%s): metrics[%d]: invalid type '%s'", i, query.Name, j, extractor.Type)
+		}
+		if extractor.Name == "" {
+			return fmt.Errorf("query[%d] (%s): metrics[%d]: name must be specified", i, query.Name, j)
+		}
+		if extractor.Type != "hits_total" && extractor.Path == "" {
+			return fmt.Errorf("query[%d] (%s): metrics[%d]: path must be specified for type '%s'", i, query.Name, j, extractor.Type)
+		}
+		if extractor.Type == "scripted_metric" && extractor.Value == "" {
+			return fmt.Errorf("query[%d] (%s): metrics[%d]: value must be specified for type 'scripted_metric'", i, query.Name, j)
+		}
+	}
+
+	if query.Pagination != nil {
+		p := query.Pagination
+		if p.Strategy != "search_after" && p.Strategy != "scroll" && p.Strategy != "pit" {
+			return fmt.Errorf("query[%d] (%s): pagination.strategy must be 'search_after', 'scroll', or 'pit'", i, query.Name)
+		}
+		if (p.Strategy == "search_after" || p.Strategy == "pit") && len(p.Sort) == 0 {
+			p.Sort = []map[string]interface{}{
+				{"@timestamp": "asc"},
+				{"_shard_doc": "asc"},
+			}
+		}
+		if p.PageSize == 0 {
+			p.PageSize = 1000
+		}
+		if p.KeepAlive == 0 {
+			p.KeepAlive = time.Minute
+		}
 	}
 
 	return nil
@@ -144,6 +674,16 @@ func (cfg *Config) GetEndpoint() string {
 	return cfg.Endpoint
 }
 
+// GetEndpoints returns every node the client should pool requests across:
+// Endpoints if any were configured, otherwise GetEndpoint() as the sole
+// node, so callers never need to special-case the single-endpoint form.
+func (cfg *Config) GetEndpoints() []string {
+	if len(cfg.Endpoints) > 0 {
+		return cfg.Endpoints
+	}
+	return []string{cfg.GetEndpoint()}
+}
+
 // UsesBasicAuth returns true if basic authentication should be used
 func (cfg *Config) UsesBasicAuth() bool {
 	return cfg.Mode == "direct" && cfg.Username != "" && cfg.Password != ""
@@ -241,6 +241,10 @@ func TestConfigDefaults(t *testing.T) {
 	if config.LookbackPeriod != 5*time.Minute {
 		t.Errorf("Expected default lookback_period 5m, got %v", config.LookbackPeriod)
 	}
+
+	if config.MaxCatchup != config.LookbackPeriod {
+		t.Errorf("Expected default max_catchup to equal lookback_period (%v), got %v", config.LookbackPeriod, config.MaxCatchup)
+	}
 }
 
 func TestConfigGetEndpoint(t *testing.T) {
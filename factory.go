@@ -8,6 +8,8 @@ import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/opensearchqueryreceiver/internal/metadata"
 )
 
 // Type identifier for the OpenSearch Query Receiver
@@ -19,6 +21,7 @@ func NewFactory() receiver.Factory {
 		typeStr,
 		createDefaultConfig,
 		receiver.WithMetrics(createMetricsReceiver, component.StabilityLevelDevelopment),
+		receiver.WithLogs(createLogsReceiver, component.StabilityLevelDevelopment),
 	)
 }
 
@@ -33,7 +36,12 @@ func createDefaultConfig() component.Config {
 		Mode:               "direct",
 		TimeField:          "@timestamp",
 		LookbackPeriod:     5 * time.Minute,
+		MaxCatchup:         5 * time.Minute,
 		Queries:            []QueryConfig{},
+		RetryOnFailure: RetryConfig{
+			Enabled: true,
+		},
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
 	}
 }
 
@@ -49,3 +57,17 @@ func createMetricsReceiver(
 	// Create and return the metrics receiver
 	return newMetricsReceiver(rCfg, consumer, settings)
 }
+
+// createLogsReceiver creates a logs receiver based on the provided config.
+// It only emits records for queries with emit_logs set; with none configured
+// the receiver starts and simply produces no logs.
+func createLogsReceiver(
+	ctx context.Context,
+	settings receiver.Settings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	rCfg := cfg.(*Config)
+
+	return newLogsReceiver(rCfg, consumer, settings)
+}
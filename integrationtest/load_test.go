@@ -0,0 +1,83 @@
+// +build integration
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"go.opentelemetry.io/collector/config/confighttp"
+
+	opensearchqueryreceiver "github.com/opensearchqueryreceiver"
+)
+
+// TestLoadScenario runs numQueries queries fanned out across numIndices
+// targets (see Config.Targets) for a bounded duration against the mock
+// server, and records CPU/RSS via gopsutil alongside the number of metric
+// data points emitted. It exists to catch regressions in the scraper loop
+// (a goroutine leak, a busy-loop, unbounded memory growth) that
+// single-query unit tests can't surface, not to assert tight resource
+// bounds, which would make this test flaky across CI hardware.
+func TestLoadScenario(t *testing.T) {
+	const (
+		numQueries  = 20
+		numIndices  = 5
+		runDuration = 5 * time.Second
+	)
+
+	mock := NewMockOpenSearchServer()
+	defer mock.Close()
+
+	cfg := opensearchqueryreceiver.NewFactory().CreateDefaultConfig().(*opensearchqueryreceiver.Config)
+	cfg.CollectionInterval = 100 * time.Millisecond
+	cfg.Queries = make([]opensearchqueryreceiver.QueryConfig, numQueries)
+	for i := range cfg.Queries {
+		cfg.Queries[i] = opensearchqueryreceiver.QueryConfig{
+			Name:  fmt.Sprintf("query_%d", i),
+			Query: map[string]interface{}{"match_all": map[string]interface{}{}},
+		}
+	}
+
+	cfg.Targets = make([]opensearchqueryreceiver.TargetConfig, numIndices)
+	for i := range cfg.Targets {
+		cfg.Targets[i] = opensearchqueryreceiver.TargetConfig{
+			Name:         fmt.Sprintf("index_%d", i),
+			ClientConfig: confighttp.ClientConfig{Endpoint: mock.URL, Timeout: 2 * time.Second},
+			Mode:         "direct",
+			IndexPattern: fmt.Sprintf("logs-%d-*", i),
+		}
+	}
+
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("failed to attach gopsutil to the test process: %v", err)
+	}
+	// Prime CPUPercent: the first call establishes the baseline sample and
+	// always returns 0.
+	_, _ = proc.CPUPercent()
+
+	sink, shutdown := startReceiver(t, cfg)
+	time.Sleep(runDuration)
+	shutdown()
+
+	cpuPercent, err := proc.CPUPercent()
+	if err != nil {
+		t.Fatalf("failed to read CPU percent: %v", err)
+	}
+	memInfo, err := proc.MemoryInfo()
+	if err != nil {
+		t.Fatalf("failed to read memory info: %v", err)
+	}
+
+	dataPoints := sink.DataPointCount()
+	t.Logf("load scenario: %d queries x %d indices over %s: cpu_percent=%.2f rss_bytes=%d data_points=%d",
+		numQueries, numIndices, runDuration, cpuPercent, memInfo.RSS, dataPoints)
+
+	if dataPoints == 0 {
+		t.Error("expected the load scenario to emit at least one metric data point")
+	}
+}
@@ -0,0 +1,117 @@
+// Package integrationtest runs the OpenSearch Query Receiver end-to-end
+// against an in-process mock OpenSearch HTTP server, exercising the retry,
+// backoff, and metric-emission behavior that the root package's unit tests
+// can't reach without a real (or real-enough) HTTP round trip. Tests in this
+// package are gated behind the "integration" build tag, matching the
+// root package's integration_test.go convention.
+package integrationtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MockOpenSearchServer is an in-process stand-in for an OpenSearch node,
+// configurable to simulate the failure modes the receiver's retry transport
+// and scraper are meant to survive: added latency, 429/503 responses,
+// partial-shard failures reported inside an otherwise-200 response, and
+// malformed _search JSON bodies.
+type MockOpenSearchServer struct {
+	*httptest.Server
+
+	mu sync.Mutex
+
+	// Latency is added before every response.
+	Latency time.Duration
+
+	// FailEvery, when > 0, fails every FailEvery-th request with
+	// FailStatus (defaulting to 503) instead of a successful response. The
+	// count covers every request the server handles (see RequestCount), and
+	// a request that lands on a failing number is failed whatever its path,
+	// except a "/" health probe.
+	FailEvery  int
+	FailStatus int
+
+	// MalformedEvery, when > 0, makes every MalformedEvery-th request
+	// (counted the same way as FailEvery) return a 200 whose body isn't
+	// valid _search response JSON.
+	MalformedEvery int
+
+	// FailedShards is reported in a successful response's _shards.failed,
+	// simulating a partial-shard failure that still returns 200.
+	FailedShards int
+
+	requestCount int64
+}
+
+// NewMockOpenSearchServer starts a mock server with no simulated failures;
+// set its exported fields before issuing requests to turn them on.
+func NewMockOpenSearchServer() *MockOpenSearchServer {
+	m := &MockOpenSearchServer{}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+// RequestCount returns the number of requests handled so far, including
+// retries and health probes.
+func (m *MockOpenSearchServer) RequestCount() int64 {
+	return atomic.LoadInt64(&m.requestCount)
+}
+
+func (m *MockOpenSearchServer) handle(w http.ResponseWriter, r *http.Request) {
+	n := atomic.AddInt64(&m.requestCount, 1)
+
+	m.mu.Lock()
+	latency, failEvery, failStatus := m.Latency, m.FailEvery, m.FailStatus
+	malformedEvery, failedShards := m.MalformedEvery, m.FailedShards
+	m.mu.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	// The node pool's health probe hits "/" and expects a plain 200,
+	// independent of the failure simulation below.
+	if r.URL.Path == "/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if failEvery > 0 && n%int64(failEvery) == 0 {
+		status := failStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"error":{"type":"unavailable_shards_exception"}}`)
+		return
+	}
+
+	if malformedEvery > 0 && n%int64(malformedEvery) == 0 {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"hits": this is not valid json`)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"took": 5,
+		"hits": map[string]interface{}{
+			"total": map[string]interface{}{"value": 100},
+			"hits":  []interface{}{},
+		},
+		"_shards": map[string]interface{}{
+			"total":      5,
+			"successful": 5 - failedShards,
+			"failed":     failedShards,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,154 @@
+// +build integration
+
+package integrationtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	opensearchqueryreceiver "github.com/opensearchqueryreceiver"
+)
+
+// newTestConfig returns a default Config pointed at endpoint with a single
+// fast-ticking match_all query, for tests that only care about one query's
+// retry/backoff/drop behavior rather than the full configuration surface.
+func newTestConfig(endpoint string) *opensearchqueryreceiver.Config {
+	cfg := opensearchqueryreceiver.NewFactory().CreateDefaultConfig().(*opensearchqueryreceiver.Config)
+	cfg.ClientConfig = confighttp.ClientConfig{Endpoint: endpoint, Timeout: 2 * time.Second}
+	cfg.CollectionInterval = 50 * time.Millisecond
+	cfg.Mode = "direct"
+	cfg.IndexPattern = "logs-*"
+	cfg.TimeField = "@timestamp"
+	cfg.Queries = []opensearchqueryreceiver.QueryConfig{
+		{
+			Name:  "all_logs",
+			Query: map[string]interface{}{"match_all": map[string]interface{}{}},
+		},
+	}
+	cfg.RetryOnFailure.InitialInterval = 10 * time.Millisecond
+	cfg.RetryOnFailure.MaxInterval = 50 * time.Millisecond
+	cfg.RetryOnFailure.MaxRetries = 3
+	cfg.RetryOnFailure.BreakerThreshold = 100 // keep the breaker out of the way; these tests exercise retry, not the breaker
+	return cfg
+}
+
+// startReceiver creates and starts a metrics receiver against cfg, returning
+// a sink of everything it emits and a func to shut it down.
+func startReceiver(t *testing.T, cfg *opensearchqueryreceiver.Config) (*consumertest.MetricsSink, func()) {
+	t.Helper()
+
+	sink := new(consumertest.MetricsSink)
+	factory := opensearchqueryreceiver.NewFactory()
+	recv, err := factory.CreateMetrics(context.Background(), receivertest.NewNopSettings(factory.Type()), cfg, sink)
+	if err != nil {
+		t.Fatalf("CreateMetrics() failed: %v", err)
+	}
+
+	if err := recv.Start(context.Background(), nil); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+
+	return sink, func() {
+		if err := recv.Shutdown(context.Background()); err != nil {
+			t.Errorf("Shutdown() failed: %v", err)
+		}
+	}
+}
+
+// waitForDataPoints polls sink until it has seen at least n metric data
+// points, failing the test if timeout elapses first.
+func waitForDataPoints(t *testing.T, sink *consumertest.MetricsSink, n int, timeout time.Duration) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if sink.DataPointCount() >= n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d data points, got %d", n, sink.DataPointCount())
+}
+
+// TestReliabilityRetriesTransientFailures verifies that a 503 on every other
+// request is absorbed by the retry transport and the receiver still emits
+// result_count metrics, rather than dropping the collection outright.
+func TestReliabilityRetriesTransientFailures(t *testing.T) {
+	mock := NewMockOpenSearchServer()
+	defer mock.Close()
+
+	mock.FailEvery = 2
+	mock.FailStatus = 503
+
+	sink, shutdown := startReceiver(t, newTestConfig(mock.URL))
+	defer shutdown()
+
+	waitForDataPoints(t, sink, 1, 5*time.Second)
+
+	if mock.RequestCount() < 2 {
+		t.Errorf("expected the mock server to see a retried request, got %d total requests", mock.RequestCount())
+	}
+}
+
+// TestReliabilityRecordsPartialShardFailures verifies that shard failures
+// reported inside an otherwise-200 response still surface on the
+// opensearch.query.shards metric rather than being silently swallowed.
+func TestReliabilityRecordsPartialShardFailures(t *testing.T) {
+	mock := NewMockOpenSearchServer()
+	defer mock.Close()
+
+	mock.FailedShards = 2
+
+	sink, shutdown := startReceiver(t, newTestConfig(mock.URL))
+	defer shutdown()
+
+	waitForDataPoints(t, sink, 1, 5*time.Second)
+
+	var sawFailedShards bool
+	for _, m := range sink.AllMetrics() {
+		rms := m.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			sms := rms.At(i).ScopeMetrics()
+			for j := 0; j < sms.Len(); j++ {
+				metrics := sms.At(j).Metrics()
+				for k := 0; k < metrics.Len(); k++ {
+					metric := metrics.At(k)
+					if metric.Name() != "opensearch.query.shards" {
+						continue
+					}
+					dps := metric.Gauge().DataPoints()
+					for d := 0; d < dps.Len(); d++ {
+						status, ok := dps.At(d).Attributes().Get("shard_status")
+						if ok && status.Str() == "failed" && dps.At(d).IntValue() == 2 {
+							sawFailedShards = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if !sawFailedShards {
+		t.Error("expected to see a failed-shards data point with value 2")
+	}
+}
+
+// TestReliabilitySurvivesMalformedResponses verifies that a malformed
+// _search body logs and drops that collection instead of crashing the
+// scraper loop, and that subsequent well-formed collections still succeed.
+func TestReliabilitySurvivesMalformedResponses(t *testing.T) {
+	mock := NewMockOpenSearchServer()
+	defer mock.Close()
+
+	mock.MalformedEvery = 2
+
+	sink, shutdown := startReceiver(t, newTestConfig(mock.URL))
+	defer shutdown()
+
+	waitForDataPoints(t, sink, 1, 5*time.Second)
+}
@@ -0,0 +1,76 @@
+package metadata
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestMetricsBuilderRecordsEnabledMetrics(t *testing.T) {
+	mbc := DefaultMetricsBuilderConfig()
+	mb := NewMetricsBuilder(mbc)
+
+	ts := pcommon.Timestamp(1234567890)
+	mb.RecordOpensearchQueryResultCountDataPoint(ts, 42, "errors_by_service")
+	mb.RecordOpensearchQueryTookDataPoint(ts, 7, "errors_by_service")
+	mb.RecordOpensearchQueryShardsDataPoint(ts, 5, "errors_by_service", "total")
+	mb.RecordOpensearchQueryShardsDataPoint(ts, 5, "errors_by_service", "successful")
+	mb.RecordOpensearchQueryPaginationPagesFetchedDataPoint(ts, 3, "errors_by_service")
+	mb.RecordOpensearchQueryPaginationPitOpenDurationDataPoint(ts, 150, "errors_by_service")
+
+	dest := pmetric.NewMetrics().ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	mb.MoveTo(dest)
+
+	metrics := dest.Metrics()
+	if metrics.Len() != 5 {
+		t.Fatalf("expected 5 distinct metrics, got %d", metrics.Len())
+	}
+
+	var shards pmetric.Metric
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == "opensearch.query.shards" {
+			shards = metrics.At(i)
+		}
+	}
+	if shards.Gauge().DataPoints().Len() != 2 {
+		t.Fatalf("expected shards metric to batch 2 data points, got %d", shards.Gauge().DataPoints().Len())
+	}
+}
+
+func TestMetricsBuilderSkipsDisabledMetrics(t *testing.T) {
+	mbc := DefaultMetricsBuilderConfig()
+	mbc.Metrics.OpensearchQueryTook.Enabled = false
+	mb := NewMetricsBuilder(mbc)
+
+	ts := pcommon.Timestamp(1234567890)
+	mb.RecordOpensearchQueryResultCountDataPoint(ts, 1, "q")
+	mb.RecordOpensearchQueryTookDataPoint(ts, 1, "q")
+
+	dest := pmetric.NewMetrics().ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	mb.MoveTo(dest)
+
+	if dest.Metrics().Len() != 1 {
+		t.Fatalf("expected disabled metric to be skipped, got %d metrics", dest.Metrics().Len())
+	}
+	if dest.Metrics().At(0).Name() != "opensearch.query.result_count" {
+		t.Errorf("expected the remaining metric to be opensearch.query.result_count, got %s", dest.Metrics().At(0).Name())
+	}
+}
+
+func TestResourceBuilderSkipsDisabledAttributes(t *testing.T) {
+	rac := DefaultResourceAttributesConfig()
+	rac.OpensearchCluster.Enabled = false
+
+	rb := NewResourceBuilder(rac)
+	rb.SetOpensearchEndpoint("https://localhost:9200")
+	rb.SetOpensearchCluster("prod")
+
+	res := rb.Emit()
+	if _, ok := res.Attributes().Get("opensearch.endpoint"); !ok {
+		t.Error("expected opensearch.endpoint to be set")
+	}
+	if _, ok := res.Attributes().Get("opensearch.cluster"); ok {
+		t.Error("expected opensearch.cluster to be skipped when disabled")
+	}
+}
@@ -0,0 +1,380 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+// logsScraper converts OpenSearch hits into OTel log records for every query
+// with EmitLogs set, running alongside the metrics scraper. It tracks a
+// per-query high-water mark so repeated collections only ingest documents
+// newer than the last successful run instead of replaying the lookback
+// window every time.
+//
+// The high-water mark is also persisted through store, so a resumed scraper
+// picks up from its last-successful end_time (bounded by Config.MaxCatchup)
+// instead of replaying the full lookback window after a restart. With no
+// Config.StorageID configured, store falls back to an in-memory-only nop
+// client and behaves exactly as before.
+type logsScraper struct {
+	config   *Config
+	client   *OpenSearchClient
+	logger   *zap.Logger
+	settings receiver.Settings
+	status   *statusReporter
+	store    *lookbackStore
+
+	mu            sync.Mutex
+	highWaterMark map[string]time.Time
+
+	cancel context.CancelFunc
+}
+
+func newLogsScraperInstance(cfg *Config, settings receiver.Settings) (*logsScraper, error) {
+	client, err := NewOpenSearchClient(cfg, settings.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return &logsScraper{
+		config:        cfg,
+		client:        client,
+		logger:        settings.Logger,
+		settings:      settings,
+		status:        newStatusReporter(settings),
+		store:         &lookbackStore{client: storage.NewNopClient(), receiverID: settings.ID},
+		highWaterMark: make(map[string]time.Time),
+	}, nil
+}
+
+func (s *logsScraper) start(ctx context.Context, host component.Host) error {
+	if err := s.client.ConfigureAuth(host); err != nil {
+		return fmt.Errorf("failed to configure auth extension: %w", err)
+	}
+
+	store, err := configureLookbackStore(ctx, host, s.settings.ID, s.config)
+	if err != nil {
+		return fmt.Errorf("failed to configure lookback storage: %w", err)
+	}
+	s.store = store
+
+	poolCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.client.StartNodePool(poolCtx)
+
+	return nil
+}
+
+func (s *logsScraper) shutdown(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if err := s.store.Close(ctx); err != nil {
+		s.logger.Warn("Failed to close lookback storage client", zap.Error(err))
+	}
+	return nil
+}
+
+// scrapeLogs executes every emit_logs query and returns the resulting log records.
+func (s *logsScraper) scrapeLogs(ctx context.Context) (plog.Logs, error) {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("opensearch.endpoint", s.config.GetEndpoint())
+	rl.Resource().Attributes().PutStr("opensearch.index_pattern", s.config.IndexPattern)
+
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(typeStr.String())
+
+	for _, queryConfig := range s.config.Queries {
+		if !queryConfig.EmitLogs {
+			continue
+		}
+		if err := s.scrapeQueryLogs(ctx, queryConfig, sl); err != nil {
+			s.logger.Error("Failed to scrape logs for query",
+				zap.String("query_name", queryConfig.Name),
+				zap.Error(err),
+			)
+			s.status.reportFailure(queryConfig.Name, err)
+		} else {
+			s.status.reportSuccess(queryConfig.Name)
+		}
+	}
+
+	return logs, nil
+}
+
+func (s *logsScraper) scrapeQueryLogs(ctx context.Context, queryConfig QueryConfig, sl plog.ScopeLogs) error {
+	since := s.sinceFor(ctx, queryConfig)
+
+	resp, err := s.client.ExecuteQuerySince(ctx, queryConfig, since)
+	if err != nil {
+		return fmt.Errorf("query execution failed: %w", err)
+	}
+
+	latest := since
+	for _, hit := range resp.Hits.Hits {
+		ts := s.hitTimestamp(hit, queryConfig)
+		if ts.After(latest) {
+			latest = ts
+		}
+		s.appendLogRecord(sl, hit, queryConfig, ts)
+	}
+
+	// addTimeRangeFilterFrom's lower bound is inclusive, so advance the
+	// watermark one nanosecond past the last-seen document's timestamp;
+	// otherwise that document (and anything sharing its timestamp) would
+	// match again, and keep matching, on every subsequent poll.
+	nextSince := latest.Add(time.Nanosecond)
+
+	s.mu.Lock()
+	s.highWaterMark[queryConfig.Name] = nextSince
+	s.mu.Unlock()
+
+	if err := s.store.Save(ctx, queryConfig.Name, nextSince); err != nil {
+		s.logger.Warn("Failed to persist lookback state",
+			zap.String("query_name", queryConfig.Name), zap.Error(err))
+	}
+
+	return nil
+}
+
+// sinceFor returns the lower time bound to query from. The in-memory
+// high-water mark is checked first (the common case, avoiding a storage
+// round trip on every collection); on a cold start it falls back to the
+// persisted end_time from store, capped at now-MaxCatchup so a long outage
+// doesn't replay more than that window, and finally to now-LookbackPeriod
+// when nothing has been persisted either.
+func (s *logsScraper) sinceFor(ctx context.Context, queryConfig QueryConfig) time.Time {
+	s.mu.Lock()
+	mark, ok := s.highWaterMark[queryConfig.Name]
+	s.mu.Unlock()
+	if ok {
+		return mark
+	}
+
+	if persisted, ok := s.store.Load(ctx, queryConfig.Name); ok {
+		since := persisted
+		if oldestAllowed := time.Now().Add(-s.config.MaxCatchup); since.Before(oldestAllowed) {
+			since = oldestAllowed
+		}
+		s.logger.Info("Resuming query from persisted lookback state",
+			zap.String("query_name", queryConfig.Name),
+			zap.Time("resume_from", since),
+			zap.Duration("resume_lag", time.Since(since)),
+		)
+		return since
+	}
+
+	return time.Now().Add(-s.config.LookbackPeriod)
+}
+
+// hitTimestamp reads the configured time field out of a hit's source,
+// falling back to the current time when absent or unparsable.
+func (s *logsScraper) hitTimestamp(hit Hit, queryConfig QueryConfig) time.Time {
+	raw, ok := hit.Source[s.config.TimeField]
+	if !ok {
+		return time.Now()
+	}
+
+	str, ok := raw.(string)
+	if !ok {
+		return time.Now()
+	}
+
+	ts, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Now()
+	}
+	return ts
+}
+
+// appendLogRecord converts a single hit into a plog.LogRecord, promoting
+// configured severity/service/trace/span fields to dedicated record fields
+// and recording the rest of the document as the log body.
+func (s *logsScraper) appendLogRecord(sl plog.ScopeLogs, hit Hit, queryConfig QueryConfig, ts time.Time) {
+	record := sl.LogRecords().AppendEmpty()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+
+	attrs := record.Attributes()
+	attrs.PutStr("query.name", queryConfig.Name)
+	attrs.PutStr("opensearch.index", hit.Index)
+	attrs.PutStr("opensearch.document_id", hit.ID)
+	for k, v := range queryConfig.Labels {
+		attrs.PutStr(k, v)
+	}
+
+	if queryConfig.SeverityField != "" {
+		if severity, ok := hit.Source[queryConfig.SeverityField].(string); ok {
+			record.SetSeverityText(severity)
+		}
+	}
+	if queryConfig.ServiceNameField != "" {
+		if serviceName, ok := hit.Source[queryConfig.ServiceNameField].(string); ok {
+			attrs.PutStr("service.name", serviceName)
+		}
+	}
+	if queryConfig.TraceIDField != "" {
+		if traceIDHex, ok := hit.Source[queryConfig.TraceIDField].(string); ok {
+			if traceID, err := decodeTraceID(traceIDHex); err == nil {
+				record.SetTraceID(traceID)
+			}
+		}
+	}
+	if queryConfig.SpanIDField != "" {
+		if spanIDHex, ok := hit.Source[queryConfig.SpanIDField].(string); ok {
+			if spanID, err := decodeSpanID(spanIDHex); err == nil {
+				record.SetSpanID(spanID)
+			}
+		}
+	}
+
+	s.setBody(record, hit, queryConfig)
+}
+
+// setBody sets the log record's body either to a single promoted field
+// (queryConfig.BodyField) or to the whole document as a structured map.
+func (s *logsScraper) setBody(record plog.LogRecord, hit Hit, queryConfig QueryConfig) {
+	if queryConfig.BodyField != "" {
+		if value, ok := hit.Source[queryConfig.BodyField]; ok {
+			if str, ok := value.(string); ok {
+				record.Body().SetStr(str)
+				return
+			}
+			if encoded, err := json.Marshal(value); err == nil {
+				record.Body().SetStr(string(encoded))
+				return
+			}
+		}
+	}
+
+	bodyMap := record.Body().SetEmptyMap()
+	if err := bodyMap.FromRaw(hit.Source); err != nil {
+		s.logger.Warn("Failed to set structured log body, falling back to JSON string",
+			zap.String("query_name", queryConfig.Name), zap.Error(err))
+		if encoded, err := json.Marshal(hit.Source); err == nil {
+			record.Body().SetStr(string(encoded))
+		}
+	}
+}
+
+func decodeTraceID(s string) (pcommon.TraceID, error) {
+	var id pcommon.TraceID
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(id) {
+		return id, fmt.Errorf("invalid trace id %q", s)
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+func decodeSpanID(s string) (pcommon.SpanID, error) {
+	var id pcommon.SpanID
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(id) {
+		return id, fmt.Errorf("invalid span id %q", s)
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// logsReceiver implements receiver.Logs, driving logsScraper on the same
+// collection_interval/initial_delay as the metrics receiver.
+type logsReceiver struct {
+	config   *Config
+	consumer consumer.Logs
+	scraper  *logsScraper
+	cancel   context.CancelFunc
+	logger   *zap.Logger
+}
+
+func newLogsReceiver(cfg *Config, consumer consumer.Logs, settings receiver.Settings) (*logsReceiver, error) {
+	scraper, err := newLogsScraperInstance(cfg, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logsReceiver{
+		config:   cfg,
+		consumer: consumer,
+		scraper:  scraper,
+		logger:   settings.Logger,
+	}, nil
+}
+
+func (r *logsReceiver) Start(ctx context.Context, host component.Host) error {
+	r.logger.Info("Starting OpenSearch Query Receiver logs pipeline")
+
+	if err := r.scraper.start(ctx, host); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+
+	go r.collectionLoop(ctx)
+
+	return nil
+}
+
+func (r *logsReceiver) Shutdown(ctx context.Context) error {
+	r.logger.Info("Shutting down OpenSearch Query Receiver logs pipeline")
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	return r.scraper.shutdown(ctx)
+}
+
+func (r *logsReceiver) collectionLoop(ctx context.Context) {
+	if r.config.InitialDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.config.InitialDelay):
+		}
+	}
+
+	ticker := time.NewTicker(r.config.CollectionInterval)
+	defer ticker.Stop()
+
+	r.collect(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.collect(ctx)
+		}
+	}
+}
+
+func (r *logsReceiver) collect(ctx context.Context) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	logs, err := r.scraper.scrapeLogs(timeoutCtx)
+	if err != nil {
+		r.logger.Error("Failed to scrape logs", zap.Error(err))
+		return
+	}
+
+	if err := r.consumer.ConsumeLogs(timeoutCtx, logs); err != nil {
+		r.logger.Error("Failed to consume logs", zap.Error(err))
+	}
+}
@@ -0,0 +1,141 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+// TestScrapeQueryLogsAdvancesWatermarkPastLastSeenDocument verifies that a
+// document returned on one poll is not returned again on the next: the
+// high-water mark must move one nanosecond past the last-seen document's
+// timestamp, and the gte filter sent to OpenSearch must carry that
+// nanosecond precision instead of rounding it away.
+func TestScrapeQueryLogsAdvancesWatermarkPastLastSeenDocument(t *testing.T) {
+	docTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var gteValues []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SearchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		boolQuery := req.Query["bool"].(map[string]interface{})
+		filter := boolQuery["filter"].([]interface{})[0].(map[string]interface{})
+		rng := filter["range"].(map[string]interface{})
+		timeRange := rng["@timestamp"].(map[string]interface{})
+		gteValues = append(gteValues, timeRange["gte"].(string))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"took":      1,
+			"timed_out": false,
+			"hits": map[string]interface{}{
+				"total": map[string]interface{}{"value": 1, "relation": "eq"},
+				"hits": []map[string]interface{}{
+					{
+						"_index":  "logs-0001",
+						"_id":     "1",
+						"_source": map[string]interface{}{"@timestamp": docTime.Format(time.RFC3339Nano), "message": "hello"},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		ClientConfig: confighttp.ClientConfig{
+			Timeout: 5 * time.Second,
+		},
+		Mode:           "direct",
+		Endpoints:      []string{server.URL},
+		IndexPattern:   "logs-*",
+		TimeField:      "@timestamp",
+		LookbackPeriod: 5 * time.Minute,
+		MaxCatchup:     5 * time.Minute,
+		Queries: []QueryConfig{
+			{
+				Name:     "test_query",
+				Query:    map[string]interface{}{"match_all": map[string]interface{}{}},
+				EmitLogs: true,
+			},
+		},
+	}
+
+	settings := receiver.Settings{
+		ID:                component.MustNewID("opensearchquery"),
+		TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()},
+	}
+	scraper, err := newLogsScraperInstance(cfg, settings)
+	if err != nil {
+		t.Fatalf("newLogsScraperInstance() failed: %v", err)
+	}
+	scraper.client.StartNodePool(context.Background())
+
+	if _, err := scraper.scrapeLogs(context.Background()); err != nil {
+		t.Fatalf("first scrapeLogs() failed: %v", err)
+	}
+	if _, err := scraper.scrapeLogs(context.Background()); err != nil {
+		t.Fatalf("second scrapeLogs() failed: %v", err)
+	}
+
+	if len(gteValues) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(gteValues))
+	}
+
+	secondGTE, err := time.Parse(time.RFC3339Nano, gteValues[1])
+	if err != nil {
+		t.Fatalf("failed to parse second gte value %q: %v", gteValues[1], err)
+	}
+	if !secondGTE.After(docTime) {
+		t.Errorf("expected the second poll's gte (%v) to be strictly after the last-seen document's timestamp (%v), so it isn't re-matched", secondGTE, docTime)
+	}
+}
+
+// TestScrapeQueryLogsResumeFromPersistedStateExcludesLastSeenDocument
+// verifies that the chunk1-1 exclusive-boundary fix also holds across a
+// restart: sinceFor's cold-start path returns whatever was persisted by
+// store.Save, so a resumed scraper must resume from the already-advanced
+// watermark, not the last document's raw timestamp.
+func TestScrapeQueryLogsResumeFromPersistedStateExcludesLastSeenDocument(t *testing.T) {
+	docTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cfg := &Config{
+		Mode:           "direct",
+		Endpoints:      []string{"http://unused.invalid"},
+		IndexPattern:   "logs-*",
+		TimeField:      "@timestamp",
+		LookbackPeriod: 5 * time.Minute,
+		MaxCatchup:     5 * time.Minute,
+	}
+	settings := receiver.Settings{
+		ID:                component.MustNewID("opensearchquery"),
+		TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()},
+	}
+
+	scraper, err := newLogsScraperInstance(cfg, settings)
+	if err != nil {
+		t.Fatalf("newLogsScraperInstance() failed: %v", err)
+	}
+	scraper.store = &lookbackStore{client: newFakeStorageClient(), receiverID: settings.ID}
+
+	if err := scraper.store.Save(context.Background(), "test_query", docTime.Add(time.Nanosecond)); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	queryConfig := QueryConfig{Name: "test_query"}
+	since := scraper.sinceFor(context.Background(), queryConfig)
+
+	if !since.After(docTime) {
+		t.Errorf("expected a resumed scraper to pick up strictly after the last-seen document (%v), got %v", docTime, since)
+	}
+}
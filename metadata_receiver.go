@@ -14,20 +14,29 @@ import (
 type metricsReceiver struct {
 	config   *Config
 	consumer consumer.Metrics
-	scraper  *scraper
+	scraper  metricsScraper
 	cancel   context.CancelFunc
 	logger   *zap.Logger
 	settings receiver.Settings
 }
 
-// newMetricsReceiver creates a new metrics receiver
+// newMetricsReceiver creates a new metrics receiver. When cfg.Targets is
+// set, it fans out collection across all targets via multiScraper;
+// otherwise it runs a single scraper against the top-level connection config.
 func newMetricsReceiver(
 	cfg *Config,
 	consumer consumer.Metrics,
 	settings receiver.Settings,
 ) (*metricsReceiver, error) {
-	// Create scraper
-	scraper, err := newScraperInstance(cfg, settings)
+	var (
+		s   metricsScraper
+		err error
+	)
+	if len(cfg.Targets) > 0 {
+		s, err = newMultiScraper(cfg, settings)
+	} else {
+		s, err = newScraperInstance(cfg, settings)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +44,7 @@ func newMetricsReceiver(
 	return &metricsReceiver{
 		config:   cfg,
 		consumer: consumer,
-		scraper:  scraper,
+		scraper:  s,
 		logger:   settings.Logger,
 		settings: settings,
 	}, nil
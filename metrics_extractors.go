@@ -0,0 +1,263 @@
+package opensearchqueryreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// statsFields lists the numeric fields OpenSearch returns for a "stats" or
+// "extended_stats" aggregation, in the order they are emitted as metrics.
+var statsFields = []string{
+	"count", "min", "max", "avg", "sum",
+	"sum_of_squares", "variance", "std_deviation",
+}
+
+// applyMetricExtractors runs each configured MetricExtractorConfig for a
+// query against its response and emits the resulting metrics. It is used
+// instead of the generic processAggregations path whenever a query declares
+// an explicit "metrics" block.
+func (s *scraper) applyMetricExtractors(
+	sm pmetric.ScopeMetrics,
+	resp *SearchResponse,
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	for _, extractor := range queryConfig.Metrics {
+		switch extractor.Type {
+		case "hits_total":
+			s.recordGaugeMetric(sm, extractor.Name, "Number of documents matching the query", extractor.Unit, float64(resp.Hits.Total.Value), queryConfig, timestamp)
+		case "terms_bucket":
+			s.extractTermsBucket(sm, resp, extractor, queryConfig, timestamp)
+		case "stats", "extended_stats":
+			s.extractStats(sm, resp, extractor, queryConfig, timestamp)
+		case "percentiles":
+			s.extractPercentiles(sm, resp, extractor, queryConfig, timestamp)
+		case "date_histogram":
+			s.extractDateHistogram(sm, resp, extractor, queryConfig, timestamp)
+		case "scripted_metric":
+			s.extractScriptedMetric(sm, resp, extractor, queryConfig, timestamp)
+		default:
+			s.logger.Warn("Unknown metric extractor type, skipping", zap.String("type", extractor.Type))
+		}
+	}
+}
+
+// resolveAggPath descends Path (dot-separated aggregation names) into
+// resp.Aggregations and returns the aggregation object found at that path.
+func resolveAggPath(resp *SearchResponse, path string) (map[string]interface{}, bool) {
+	current := resp.Aggregations
+	var node map[string]interface{}
+
+	for _, name := range strings.Split(path, ".") {
+		value, ok := current[name]
+		if !ok {
+			return nil, false
+		}
+		node, ok = value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = node
+	}
+
+	return node, node != nil
+}
+
+func (s *scraper) extractStats(sm pmetric.ScopeMetrics, resp *SearchResponse, extractor MetricExtractorConfig, queryConfig QueryConfig, timestamp pcommon.Timestamp) {
+	agg, ok := resolveAggPath(resp, extractor.Path)
+	if !ok {
+		return
+	}
+
+	for _, field := range statsFields {
+		value, ok := agg[field].(float64)
+		if !ok {
+			continue
+		}
+		name := fmt.Sprintf("%s.%s", extractor.Name, field)
+		s.recordGaugeMetric(sm, name, fmt.Sprintf("%s aggregation %s", extractor.Path, field), extractor.Unit, value, queryConfig, timestamp)
+	}
+
+	if bounds, ok := agg["std_deviation_bounds"].(map[string]interface{}); ok {
+		if upper, ok := bounds["upper"].(float64); ok {
+			s.recordGaugeMetric(sm, fmt.Sprintf("%s.std_deviation_bounds.upper", extractor.Name), "Upper standard deviation bound", extractor.Unit, upper, queryConfig, timestamp)
+		}
+		if lower, ok := bounds["lower"].(float64); ok {
+			s.recordGaugeMetric(sm, fmt.Sprintf("%s.std_deviation_bounds.lower", extractor.Name), "Lower standard deviation bound", extractor.Unit, lower, queryConfig, timestamp)
+		}
+	}
+}
+
+func (s *scraper) extractPercentiles(sm pmetric.ScopeMetrics, resp *SearchResponse, extractor MetricExtractorConfig, queryConfig QueryConfig, timestamp pcommon.Timestamp) {
+	agg, ok := resolveAggPath(resp, extractor.Path)
+	if !ok {
+		return
+	}
+
+	values, ok := agg["values"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for percentile, raw := range values {
+		value, ok := raw.(float64)
+		if !ok {
+			continue
+		}
+		dp := s.newDataPoint(sm, extractor.Name, fmt.Sprintf("%s percentiles", extractor.Path), extractor.Unit, value, timestamp)
+		attrs := dp.Attributes()
+		attrs.PutStr("query.name", queryConfig.Name)
+		attrs.PutStr("percentile", percentile)
+		for k, v := range queryConfig.Labels {
+			attrs.PutStr(k, v)
+		}
+	}
+}
+
+func (s *scraper) extractTermsBucket(sm pmetric.ScopeMetrics, resp *SearchResponse, extractor MetricExtractorConfig, queryConfig QueryConfig, timestamp pcommon.Timestamp) {
+	agg, ok := resolveAggPath(resp, extractor.Path)
+	if !ok {
+		return
+	}
+	buckets, ok := agg["buckets"].([]interface{})
+	if !ok {
+		return
+	}
+
+	keyAttr := extractor.Attributes["key"]
+	if keyAttr == "" {
+		keyAttr = "bucket.key"
+	}
+
+	for _, raw := range buckets {
+		bucket, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		value, ok := bucketValue(bucket, extractor.Value)
+		if !ok {
+			continue
+		}
+
+		dp := s.newDataPoint(sm, extractor.Name, fmt.Sprintf("%s terms bucket", extractor.Path), extractor.Unit, value, timestamp)
+		attrs := dp.Attributes()
+		attrs.PutStr("query.name", queryConfig.Name)
+		attrs.PutStr(keyAttr, bucketKeyString(bucket))
+		for k, v := range queryConfig.Labels {
+			attrs.PutStr(k, v)
+		}
+	}
+}
+
+func (s *scraper) extractDateHistogram(sm pmetric.ScopeMetrics, resp *SearchResponse, extractor MetricExtractorConfig, queryConfig QueryConfig, timestamp pcommon.Timestamp) {
+	agg, ok := resolveAggPath(resp, extractor.Path)
+	if !ok {
+		return
+	}
+	buckets, ok := agg["buckets"].([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, raw := range buckets {
+		bucket, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		value, ok := bucketValue(bucket, extractor.Value)
+		if !ok {
+			continue
+		}
+
+		bucketTimestamp := timestamp
+		if keyMillis, ok := bucket["key"].(float64); ok {
+			bucketTimestamp = pcommon.NewTimestampFromTime(time.UnixMilli(int64(keyMillis)))
+		}
+
+		dp := s.newDataPoint(sm, extractor.Name, fmt.Sprintf("%s date histogram bucket", extractor.Path), extractor.Unit, value, bucketTimestamp)
+		attrs := dp.Attributes()
+		attrs.PutStr("query.name", queryConfig.Name)
+		attrs.PutStr("bucket.key", bucketKeyString(bucket))
+		for k, v := range queryConfig.Labels {
+			attrs.PutStr(k, v)
+		}
+	}
+}
+
+func (s *scraper) extractScriptedMetric(sm pmetric.ScopeMetrics, resp *SearchResponse, extractor MetricExtractorConfig, queryConfig QueryConfig, timestamp pcommon.Timestamp) {
+	agg, ok := resolveAggPath(resp, extractor.Path)
+	if !ok {
+		return
+	}
+
+	value, ok := bucketValue(agg, extractor.Value)
+	if !ok {
+		return
+	}
+
+	s.recordGaugeMetric(sm, extractor.Name, fmt.Sprintf("%s scripted metric", extractor.Path), extractor.Unit, value, queryConfig, timestamp)
+}
+
+// bucketValue resolves a dot-separated pointer within bucket, defaulting to
+// "doc_count" when pointer is empty.
+func bucketValue(bucket map[string]interface{}, pointer string) (float64, bool) {
+	if pointer == "" {
+		pointer = "doc_count"
+	}
+
+	var current interface{} = bucket
+	for _, segment := range strings.Split(pointer, ".") {
+		asMap, ok := current.(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		current, ok = asMap[segment]
+		if !ok {
+			return 0, false
+		}
+	}
+
+	value, ok := current.(float64)
+	return value, ok
+}
+
+// bucketKeyString renders a bucket's key as a string, preferring
+// key_as_string (e.g. ISO timestamps) when present.
+func bucketKeyString(bucket map[string]interface{}) string {
+	if keyStr, ok := bucket["key_as_string"].(string); ok {
+		return keyStr
+	}
+	switch key := bucket["key"].(type) {
+	case string:
+		return key
+	case float64:
+		return strconv.FormatFloat(key, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// newDataPoint appends a new gauge metric with name/description/unit to sm
+// and returns its first (only) data point for the caller to attach
+// attributes to.
+func (s *scraper) newDataPoint(sm pmetric.ScopeMetrics, name, description, unit string, value float64, timestamp pcommon.Timestamp) pmetric.NumberDataPoint {
+	metric := sm.Metrics().AppendEmpty()
+	metric.SetName(name)
+	metric.SetDescription(description)
+	metric.SetUnit(unit)
+
+	gauge := metric.SetEmptyGauge()
+	dp := gauge.DataPoints().AppendEmpty()
+	dp.SetTimestamp(timestamp)
+	dp.SetDoubleValue(value)
+
+	return dp
+}
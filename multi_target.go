@@ -0,0 +1,160 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+// metricsScraper is implemented by both the single-cluster scraper and the
+// multi-target fan-out scraper below, so metricsReceiver can drive either
+// uniformly.
+type metricsScraper interface {
+	start(ctx context.Context, host component.Host) error
+	shutdown(ctx context.Context) error
+	scrape(ctx context.Context) (pmetric.Metrics, error)
+}
+
+// multiScraper fans out collection across Config.Targets, running one
+// *scraper per target concurrently (bounded by a worker pool) and merging
+// their metrics into a single pmetric.Metrics, tagged with an
+// opensearch.cluster resource attribute per target.
+type multiScraper struct {
+	logger      *zap.Logger
+	maxParallel int
+	targets     []*targetScraper
+}
+
+// targetScraper pairs a target's name with the single-cluster scraper
+// collecting for it.
+type targetScraper struct {
+	name    string
+	scraper *scraper
+}
+
+// newMultiScraper builds a scraper per configured target, deriving each
+// target's effective Config from the top-level Config plus its TargetConfig
+// overrides.
+func newMultiScraper(cfg *Config, settings receiver.Settings) (*multiScraper, error) {
+	targets := make([]*targetScraper, 0, len(cfg.Targets))
+
+	for _, target := range cfg.Targets {
+		queries, err := target.resolveQueries(cfg.Queries)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %w", target.Name, err)
+		}
+
+		targetCfg := *cfg
+		targetCfg.ClientConfig = target.ClientConfig
+		targetCfg.Mode = target.Mode
+		targetCfg.Username = target.Username
+		targetCfg.Password = target.Password
+		targetCfg.ProxyEndpoint = target.ProxyEndpoint
+		targetCfg.OAuth2 = target.OAuth2
+		targetCfg.SigV4 = target.SigV4
+		targetCfg.IndexPattern = target.IndexPattern
+		targetCfg.Queries = queries
+		targetCfg.Targets = nil
+		if target.RetryOnFailure != (RetryConfig{}) {
+			targetCfg.RetryOnFailure = target.RetryOnFailure
+		}
+		targetCfg.RetryOnFailure.setDefaults()
+
+		s, err := newScraperInstance(&targetCfg, settings)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %w", target.Name, err)
+		}
+
+		targets = append(targets, &targetScraper{name: target.Name, scraper: s})
+	}
+
+	maxParallel := cfg.MaxConcurrentTargets
+	if maxParallel <= 0 {
+		maxParallel = len(targets)
+		if maxParallel > 8 {
+			maxParallel = 8
+		}
+	}
+
+	return &multiScraper{
+		logger:      settings.Logger,
+		maxParallel: maxParallel,
+		targets:     targets,
+	}, nil
+}
+
+// start pings every target so startup health is visible, but never fails
+// Start just because some targets are unreachable.
+func (m *multiScraper) start(ctx context.Context, host component.Host) error {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, m.maxParallel)
+
+	for _, t := range m.targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t *targetScraper) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := t.scraper.start(ctx, host); err != nil {
+				m.logger.Warn("Failed to start target", zap.String("target", t.name), zap.Error(err))
+			}
+		}(t)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (m *multiScraper) shutdown(ctx context.Context) error {
+	for _, t := range m.targets {
+		if err := t.scraper.shutdown(ctx); err != nil {
+			m.logger.Warn("Failed to shut down target", zap.String("target", t.name), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// scrape collects from every target concurrently, bounded by maxParallel,
+// and merges the resulting resource metrics into one pmetric.Metrics.
+func (m *multiScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	merged := pmetric.NewMetrics()
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, m.maxParallel)
+	)
+
+	for _, t := range m.targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t *targetScraper) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			targetMetrics, err := t.scraper.scrape(ctx)
+			if err != nil {
+				m.logger.Error("Failed to scrape target", zap.String("target", t.name), zap.Error(err))
+				return
+			}
+
+			for i := 0; i < targetMetrics.ResourceMetrics().Len(); i++ {
+				rm := targetMetrics.ResourceMetrics().At(i)
+				rm.Resource().Attributes().PutStr("opensearch.cluster", t.name)
+			}
+
+			mu.Lock()
+			targetMetrics.ResourceMetrics().MoveAndAppendTo(merged.ResourceMetrics())
+			mu.Unlock()
+		}(t)
+	}
+
+	wg.Wait()
+	return merged, nil
+}
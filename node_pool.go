@@ -0,0 +1,350 @@
+package opensearchqueryreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// poolNode is a single OpenSearch coordinator node tracked by a nodePool.
+type poolNode struct {
+	scheme string
+	host   string
+
+	healthy bool
+}
+
+// key identifies a node independent of its health state, used as the map
+// key for marking it healthy/unhealthy and for de-duplicating sniff results.
+func (n *poolNode) key() string {
+	return n.scheme + "://" + n.host
+}
+
+func newPoolNode(endpoint string) (*poolNode, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	return &poolNode{scheme: u.Scheme, host: u.Host, healthy: true}, nil
+}
+
+// nodePool round-robins requests across a multi-node OpenSearch cluster,
+// steering around nodes that have recently errored or returned a 5xx
+// instead of retrying the same unreachable coordinator every scrape. A
+// background loop re-probes unhealthy nodes with GET / and, when
+// Config.Sniff is set, periodically discovers the cluster's full node list
+// via GET /_nodes/http.
+type nodePool struct {
+	logger *zap.Logger
+	sniff  bool
+
+	probeInterval time.Duration
+	sniffInterval time.Duration
+
+	mu     sync.Mutex
+	nodes  []*poolNode
+	cursor int
+}
+
+func newNodePool(endpoints []string, sniff bool, logger *zap.Logger) (*nodePool, error) {
+	p := &nodePool{
+		logger:        logger,
+		sniff:         sniff,
+		probeInterval: 30 * time.Second,
+		sniffInterval: 5 * time.Minute,
+	}
+
+	for _, endpoint := range endpoints {
+		node, err := newPoolNode(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		p.nodes = append(p.nodes, node)
+	}
+
+	if len(p.nodes) == 0 {
+		return nil, errors.New("node pool requires at least one endpoint")
+	}
+
+	return p, nil
+}
+
+// next returns the next node to try that isn't in tried, preferring a
+// healthy one but falling back to an untried unhealthy node rather than
+// failing the request outright when every node is currently marked down.
+func (p *nodePool) next(tried map[string]bool) (*poolNode, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var fallback *poolNode
+	for i := 0; i < len(p.nodes); i++ {
+		idx := (p.cursor + i) % len(p.nodes)
+		n := p.nodes[idx]
+		if tried[n.key()] {
+			continue
+		}
+		if fallback == nil {
+			fallback = n
+		}
+		if n.healthy {
+			p.cursor = (idx + 1) % len(p.nodes)
+			return n, true
+		}
+	}
+
+	if fallback == nil {
+		return nil, false
+	}
+	p.cursor = (p.cursor + 1) % len(p.nodes)
+	return fallback, true
+}
+
+func (p *nodePool) markUnhealthy(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, n := range p.nodes {
+		if n.key() != key {
+			continue
+		}
+		if n.healthy && p.logger != nil {
+			p.logger.Warn("Marking OpenSearch node unhealthy", zap.String("node", key))
+		}
+		n.healthy = false
+		return
+	}
+}
+
+func (p *nodePool) markHealthy(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, n := range p.nodes {
+		if n.key() != key {
+			continue
+		}
+		if !n.healthy && p.logger != nil {
+			p.logger.Info("OpenSearch node returned to rotation", zap.String("node", key))
+		}
+		n.healthy = true
+		return
+	}
+}
+
+func (p *nodePool) snapshot() []*poolNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]*poolNode, len(p.nodes))
+	copy(out, p.nodes)
+	return out
+}
+
+// addDiscovered merges nodes found via sniffing into the pool, leaving the
+// health state of already-known nodes untouched.
+func (p *nodePool) addDiscovered(discovered []*poolNode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	known := make(map[string]bool, len(p.nodes))
+	for _, n := range p.nodes {
+		known[n.key()] = true
+	}
+	for _, n := range discovered {
+		if known[n.key()] {
+			continue
+		}
+		p.nodes = append(p.nodes, n)
+		known[n.key()] = true
+	}
+}
+
+// run drives the pool's background health probing and, if sniff is
+// enabled, periodic cluster discovery. It blocks until ctx is cancelled, so
+// callers should invoke it in its own goroutine.
+func (p *nodePool) run(ctx context.Context, probeClient *http.Client) {
+	if p.sniff {
+		if err := p.sniffOnce(ctx, probeClient); err != nil && p.logger != nil {
+			p.logger.Warn("Initial OpenSearch node sniff failed", zap.Error(err))
+		}
+	}
+
+	probeTicker := time.NewTicker(p.probeInterval)
+	defer probeTicker.Stop()
+
+	var sniffC <-chan time.Time
+	if p.sniff {
+		sniffTicker := time.NewTicker(p.sniffInterval)
+		defer sniffTicker.Stop()
+		sniffC = sniffTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-probeTicker.C:
+			p.probeUnhealthy(ctx, probeClient)
+		case <-sniffC:
+			if err := p.sniffOnce(ctx, probeClient); err != nil && p.logger != nil {
+				p.logger.Warn("Periodic OpenSearch node sniff failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// probeUnhealthy issues GET / against every node currently marked down and
+// returns it to rotation the moment it answers without a 5xx.
+func (p *nodePool) probeUnhealthy(ctx context.Context, client *http.Client) {
+	for _, n := range p.snapshot() {
+		if n.healthy {
+			continue
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.scheme+"://"+n.host+"/", nil)
+		if err != nil {
+			continue
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < http.StatusInternalServerError {
+			p.markHealthy(n.key())
+		}
+	}
+}
+
+// sniffNodesResponse is the shape of GET /_nodes/http's response, trimmed
+// to the field needed to resolve each node's publish address.
+type sniffNodesResponse struct {
+	Nodes map[string]struct {
+		HTTP struct {
+			PublishAddress string `json:"publish_address"`
+		} `json:"http"`
+	} `json:"nodes"`
+}
+
+// sniffOnce queries one known node for the cluster's full member list and
+// merges any newly discovered nodes into the pool.
+func (p *nodePool) sniffOnce(ctx context.Context, client *http.Client) error {
+	seeds := p.snapshot()
+	if len(seeds) == 0 {
+		return errors.New("no nodes available to sniff from")
+	}
+	seed := seeds[0]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, seed.scheme+"://"+seed.host+"/_nodes/http", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var parsed sniffNodesResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return fmt.Errorf("failed to parse _nodes/http response: %w", err)
+	}
+
+	var discovered []*poolNode
+	for _, info := range parsed.Nodes {
+		if info.HTTP.PublishAddress == "" {
+			continue
+		}
+		discovered = append(discovered, &poolNode{scheme: seed.scheme, host: info.HTTP.PublishAddress, healthy: true})
+	}
+
+	if len(discovered) > 0 {
+		p.addDiscovered(discovered)
+		if p.logger != nil {
+			p.logger.Debug("Sniffed OpenSearch cluster nodes", zap.Int("discovered", len(discovered)))
+		}
+	}
+
+	return nil
+}
+
+// nodePoolTransport wraps the retryTransport-and-below chain and steers
+// each request to a node from the pool, failing over to the next node on a
+// connection error or 5xx instead of surfacing the failure to the caller.
+// Per-node retries (backoff, circuit breaker) still happen one layer down
+// in retryTransport, keyed by host and request path, so each node (and each
+// path on it) gets its own breaker.
+type nodePoolTransport struct {
+	base http.RoundTripper
+	pool *nodePool
+}
+
+func newNodePoolTransport(base http.RoundTripper, pool *nodePool) *nodePoolTransport {
+	return &nodePoolTransport{base: base, pool: pool}
+}
+
+func (t *nodePoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body for node failover: %w", err)
+		}
+		req.Body.Close()
+		bodyBytes = b
+	}
+
+	tried := make(map[string]bool)
+	var lastErr error
+
+	for {
+		node, ok := t.pool.next(tried)
+		if !ok {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, errors.New("opensearchqueryreceiver: no OpenSearch node available")
+		}
+		tried[node.key()] = true
+
+		attemptReq := req.Clone(req.Context())
+		attemptReq.URL.Scheme = node.scheme
+		attemptReq.URL.Host = node.host
+		attemptReq.Host = ""
+		if bodyBytes != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+			t.pool.markUnhealthy(node.key())
+			if err != nil {
+				lastErr = err
+			} else {
+				lastErr = fmt.Errorf("node %s returned status %d", node.key(), resp.StatusCode)
+				resp.Body.Close()
+			}
+			continue
+		}
+
+		t.pool.markHealthy(node.key())
+		return resp, nil
+	}
+}
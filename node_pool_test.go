@@ -0,0 +1,105 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
+)
+
+func TestExecuteQueryFailsOverToHealthyNode(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	var healthyHits int
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthyHits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`))
+	}))
+	defer healthy.Close()
+
+	cfg := &Config{
+		ClientConfig: confighttp.ClientConfig{
+			Timeout: 5 * time.Second,
+		},
+		Mode:           "direct",
+		Endpoints:      []string{down.URL, healthy.URL},
+		IndexPattern:   "logs-*",
+		TimeField:      "@timestamp",
+		LookbackPeriod: 5 * time.Minute,
+	}
+
+	client, err := NewOpenSearchClient(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewOpenSearchClient() failed: %v", err)
+	}
+
+	query := QueryConfig{Name: "test", Query: map[string]interface{}{"match_all": map[string]interface{}{}}}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.ExecuteQuery(ctx, query); err != nil {
+			t.Fatalf("ExecuteQuery() failed: %v", err)
+		}
+	}
+
+	if healthyHits == 0 {
+		t.Error("expected at least one request to reach the healthy node")
+	}
+}
+
+func TestNodePoolNextSkipsUnhealthyUntilExhausted(t *testing.T) {
+	pool, err := newNodePool([]string{"http://node-a", "http://node-b"}, false, zap.NewNop())
+	if err != nil {
+		t.Fatalf("newNodePool() failed: %v", err)
+	}
+
+	pool.markUnhealthy("http://node-a")
+
+	node, ok := pool.next(map[string]bool{})
+	if !ok {
+		t.Fatal("expected a node to be returned")
+	}
+	if node.key() != "http://node-b" {
+		t.Errorf("expected healthy node-b to be picked first, got %s", node.key())
+	}
+
+	node, ok = pool.next(map[string]bool{"http://node-b": true})
+	if !ok {
+		t.Fatal("expected a fallback node when only unhealthy nodes remain untried")
+	}
+	if node.key() != "http://node-a" {
+		t.Errorf("expected unhealthy node-a as fallback, got %s", node.key())
+	}
+
+	_, ok = pool.next(map[string]bool{"http://node-a": true, "http://node-b": true})
+	if ok {
+		t.Error("expected no node once every node has been tried")
+	}
+}
+
+func TestNodePoolProbeRestoresHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pool, err := newNodePool([]string{server.URL}, false, zap.NewNop())
+	if err != nil {
+		t.Fatalf("newNodePool() failed: %v", err)
+	}
+	pool.markUnhealthy(pool.nodes[0].key())
+
+	pool.probeUnhealthy(context.Background(), server.Client())
+
+	if !pool.nodes[0].healthy {
+		t.Error("expected node to be marked healthy again after a successful probe")
+	}
+}
@@ -0,0 +1,161 @@
+package opensearchqueryreceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtls"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuth2Config configures in-process OAuth2 client-credentials authentication.
+// When Config.Mode is "oauth2", the client fetches, caches, and refreshes
+// bearer tokens from TokenURL and attaches them to every request sent to
+// OpenSearch, removing the need to stand up a separate OAuth2 proxy.
+type OAuth2Config struct {
+	// TokenURL is the OAuth2 token endpoint, e.g. https://idp.example.com/oauth2/token
+	TokenURL string `mapstructure:"token_url"`
+
+	// ClientID is the OAuth2 client identifier
+	ClientID string `mapstructure:"client_id"`
+
+	// ClientSecret is the OAuth2 client secret
+	ClientSecret string `mapstructure:"client_secret"`
+
+	// Scopes is the list of OAuth2 scopes to request
+	Scopes []string `mapstructure:"scopes"`
+
+	// Audience is an optional audience parameter required by some providers
+	Audience string `mapstructure:"audience"`
+
+	// TLS configures mTLS for the token endpoint (client certs, CA, etc.)
+	TLS configtls.ClientConfig `mapstructure:"tls"`
+
+	// Timeout bounds each token acquisition request. Defaults to 10s.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// MaxRetries is the number of times to retry a failed token acquisition
+	// before giving up. Defaults to 3.
+	MaxRetries int `mapstructure:"max_retries"`
+
+	// RetryInterval is the delay between token acquisition retries. Defaults to 1s.
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+}
+
+// validate checks that the OAuth2 configuration is usable and fills in defaults.
+func (o *OAuth2Config) validate() error {
+	if o.TokenURL == "" {
+		return errors.New("oauth2.token_url must be specified")
+	}
+	if o.ClientID == "" {
+		return errors.New("oauth2.client_id must be specified")
+	}
+	if o.ClientSecret == "" {
+		return errors.New("oauth2.client_secret must be specified")
+	}
+
+	if o.Timeout == 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.RetryInterval == 0 {
+		o.RetryInterval = time.Second
+	}
+
+	return nil
+}
+
+// newOAuth2Transport wraps base with an http.RoundTripper that transparently
+// fetches and refreshes bearer tokens from the configured OAuth2 token
+// endpoint before each request. Token acquisition failures are retried with
+// a fixed backoff so a temporary auth outage doesn't fail the whole scrape.
+func newOAuth2Transport(ctx context.Context, cfg OAuth2Config, base http.RoundTripper) (http.RoundTripper, error) {
+	tlsConfig, err := cfg.TLS.LoadTLSConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load oauth2 tls config: %w", err)
+	}
+
+	tokenHTTPClient := &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &retryingRoundTripper{
+			base:       &http.Transport{TLSClientConfig: tlsConfig},
+			maxRetries: cfg.MaxRetries,
+			interval:   cfg.RetryInterval,
+		},
+	}
+
+	ccCfg := &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     cfg.TokenURL,
+		Scopes:       cfg.Scopes,
+	}
+	if cfg.Audience != "" {
+		ccCfg.EndpointParams = url.Values{"audience": {cfg.Audience}}
+	}
+
+	tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, tokenHTTPClient)
+
+	return &oauth2.Transport{
+		Source: ccCfg.TokenSource(tokenCtx),
+		Base:   base,
+	}, nil
+}
+
+// retryingRoundTripper retries failed requests with a fixed interval. It is
+// used to make OAuth2 token acquisition resilient to transient outages on
+// the token endpoint; it is intentionally simple compared to the receiver's
+// main retry/circuit-breaker transport, since token requests are low volume.
+type retryingRoundTripper struct {
+	base       http.RoundTripper
+	maxRetries int
+	interval   time.Duration
+}
+
+func (t *retryingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer oauth2 token request body for retry: %w", err)
+		}
+		req.Body.Close()
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= t.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(t.interval):
+			}
+		}
+
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		lastErr = fmt.Errorf("oauth2 token endpoint returned status %d", resp.StatusCode)
+		resp.Body.Close()
+	}
+	return nil, fmt.Errorf("oauth2 token acquisition failed after %d attempts: %w", t.maxRetries+1, lastErr)
+}
@@ -0,0 +1,442 @@
+package opensearchqueryreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// pitResponse is the response to a point-in-time creation request.
+type pitResponse struct {
+	PitID string `json:"pit_id"`
+}
+
+// HitVisitor is called once per hit streamed back by ExecutePaginatedQuery.
+// Returning an error aborts pagination.
+type HitVisitor func(hit Hit) error
+
+// PaginationStats reports how a paginated query's collection cycle went, so
+// the scraper can expose it via the receiver's internal pagination metrics
+// (opensearch.query.pagination.pages_fetched and
+// .pit_open_duration).
+type PaginationStats struct {
+	// PagesFetched is the number of pages fetched, across all strategies.
+	PagesFetched int
+
+	// PITOpenDuration is how long the point-in-time context stayed open,
+	// from creation to close. Zero for the "search_after" and "scroll"
+	// strategies.
+	PITOpenDuration time.Duration
+}
+
+// ExecutePaginatedQuery streams hits for query through whichever pagination
+// strategy its Pagination block selects, invoking visit for every hit
+// instead of buffering the whole result set in memory. Pagination stops when
+// a page comes back short (no more results), when query.Pagination.MaxPages
+// or MaxHits is reached, or when ctx is cancelled.
+func (c *OpenSearchClient) ExecutePaginatedQuery(ctx context.Context, query QueryConfig, visit HitVisitor) (*PaginationStats, error) {
+	p := query.Pagination
+	if p == nil {
+		return nil, fmt.Errorf("query %q has no pagination configuration", query.Name)
+	}
+
+	switch p.Strategy {
+	case "pit":
+		return c.executePIT(ctx, query, visit)
+	case "search_after":
+		return c.executeSearchAfter(ctx, query, visit)
+	case "scroll":
+		return c.executeScroll(ctx, query, visit)
+	default:
+		return nil, fmt.Errorf("unknown pagination strategy %q", p.Strategy)
+	}
+}
+
+func (c *OpenSearchClient) executePIT(ctx context.Context, query QueryConfig, visit HitVisitor) (stats *PaginationStats, err error) {
+	p := query.Pagination
+	stats = &PaginationStats{}
+
+	opened := time.Now()
+	pitID, err := c.openPIT(ctx, p.KeepAlive)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open point-in-time: %w", err)
+	}
+	defer func() {
+		stats.PITOpenDuration = time.Since(opened)
+		if closeErr := c.closePIT(context.Background(), pitID); closeErr != nil {
+			c.logger.Warn("Failed to close point-in-time", zap.Error(closeErr))
+		}
+	}()
+
+	var searchAfter []interface{}
+	totalHits := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		if p.MaxPages > 0 && stats.PagesFetched >= p.MaxPages {
+			break
+		}
+
+		searchReq := SearchRequest{
+			Query: c.addTimeRangeFilter(query.Query),
+			Size:  p.PageSize,
+		}
+
+		reqBody := map[string]interface{}{
+			"query": searchReq.Query,
+			"size":  searchReq.Size,
+			"sort":  p.Sort,
+			"pit":   map[string]interface{}{"id": pitID, "keep_alive": p.KeepAlive.String()},
+		}
+		if len(searchAfter) > 0 {
+			reqBody["search_after"] = searchAfter
+		}
+
+		resp, err := c.doSearch(ctx, "/_search", reqBody)
+		if err != nil {
+			return stats, err
+		}
+
+		if len(resp.Hits.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range resp.Hits.Hits {
+			if err := visit(hit); err != nil {
+				return stats, err
+			}
+			totalHits++
+			if p.MaxHits > 0 && totalHits >= p.MaxHits {
+				return stats, nil
+			}
+		}
+
+		searchAfter = hitSortValues(resp.Hits.Hits[len(resp.Hits.Hits)-1])
+		if searchAfter == nil {
+			break
+		}
+
+		stats.PagesFetched++
+		if len(resp.Hits.Hits) < p.PageSize {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+func (c *OpenSearchClient) executeSearchAfter(ctx context.Context, query QueryConfig, visit HitVisitor) (*PaginationStats, error) {
+	p := query.Pagination
+	stats := &PaginationStats{}
+
+	var searchAfter []interface{}
+	totalHits := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		if p.MaxPages > 0 && stats.PagesFetched >= p.MaxPages {
+			break
+		}
+
+		reqBody := map[string]interface{}{
+			"query": c.addTimeRangeFilter(query.Query),
+			"size":  p.PageSize,
+			"sort":  p.Sort,
+		}
+		if len(searchAfter) > 0 {
+			reqBody["search_after"] = searchAfter
+		}
+
+		url := fmt.Sprintf("/%s/_search", c.config.IndexPattern)
+		resp, err := c.doSearch(ctx, url, reqBody)
+		if err != nil {
+			return stats, err
+		}
+
+		if len(resp.Hits.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range resp.Hits.Hits {
+			if err := visit(hit); err != nil {
+				return stats, err
+			}
+			totalHits++
+			if p.MaxHits > 0 && totalHits >= p.MaxHits {
+				return stats, nil
+			}
+		}
+
+		searchAfter = hitSortValues(resp.Hits.Hits[len(resp.Hits.Hits)-1])
+		if searchAfter == nil {
+			break
+		}
+
+		stats.PagesFetched++
+		if len(resp.Hits.Hits) < p.PageSize {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+func (c *OpenSearchClient) executeScroll(ctx context.Context, query QueryConfig, visit HitVisitor) (*PaginationStats, error) {
+	p := query.Pagination
+	stats := &PaginationStats{}
+
+	url := fmt.Sprintf("/%s/_search?scroll=%s", c.config.IndexPattern, p.KeepAlive.String())
+	reqBody := map[string]interface{}{
+		"query": c.addTimeRangeFilter(query.Query),
+		"size":  p.PageSize,
+	}
+
+	resp, scrollID, err := c.doScrollSearch(ctx, url, reqBody)
+	if err != nil {
+		return stats, err
+	}
+
+	var scrollCloseID string
+	defer func() {
+		if scrollCloseID != "" {
+			if closeErr := c.closeScroll(context.Background(), scrollCloseID); closeErr != nil {
+				c.logger.Warn("Failed to close scroll context", zap.Error(closeErr))
+			}
+		}
+	}()
+
+	totalHits := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		scrollCloseID = scrollID
+
+		if len(resp.Hits.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range resp.Hits.Hits {
+			if err := visit(hit); err != nil {
+				return stats, err
+			}
+			totalHits++
+			if p.MaxHits > 0 && totalHits >= p.MaxHits {
+				return stats, nil
+			}
+		}
+
+		stats.PagesFetched++
+		if p.MaxPages > 0 && stats.PagesFetched >= p.MaxPages {
+			break
+		}
+		if len(resp.Hits.Hits) < p.PageSize {
+			break
+		}
+
+		scrollReqBody := map[string]interface{}{
+			"scroll":    p.KeepAlive.String(),
+			"scroll_id": scrollID,
+		}
+		resp, scrollID, err = c.doScrollSearch(ctx, "/_search/scroll", scrollReqBody)
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// doSearch issues a raw _search request with an arbitrary body and parses
+// the response, used by the pagination strategies above.
+func (c *OpenSearchClient) doSearch(ctx context.Context, path string, body map[string]interface{}) (*SearchResponse, error) {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal paginated search request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s%s", c.config.GetEndpoint(), path)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create paginated search request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.UsesBasicAuth() {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute paginated search request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read paginated search response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var searchResp SearchResponse
+	if err := json.Unmarshal(respBody, &searchResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal paginated search response: %w", err)
+	}
+
+	return &searchResp, nil
+}
+
+// doScrollSearch is doSearch plus extraction of the returned scroll_id.
+func (c *OpenSearchClient) doScrollSearch(ctx context.Context, path string, body map[string]interface{}) (*SearchResponse, string, error) {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal scroll request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s%s", c.config.GetEndpoint(), path)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create scroll request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.UsesBasicAuth() {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to execute scroll request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read scroll response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status code %d: %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var raw struct {
+		SearchResponse
+		ScrollID string `json:"_scroll_id"`
+	}
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal scroll response: %w", err)
+	}
+
+	return &raw.SearchResponse, raw.ScrollID, nil
+}
+
+// openPIT obtains a point-in-time id for the receiver's configured index pattern.
+func (c *OpenSearchClient) openPIT(ctx context.Context, keepAlive time.Duration) (string, error) {
+	url := fmt.Sprintf("%s/%s/_pit?keep_alive=%s", c.config.GetEndpoint(), c.config.IndexPattern, keepAlive.String())
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create pit request: %w", err)
+	}
+	if c.config.UsesBasicAuth() {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to open pit: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read pit response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d opening pit: %s", httpResp.StatusCode, string(body))
+	}
+
+	var pitResp pitResponse
+	if err := json.Unmarshal(body, &pitResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal pit response: %w", err)
+	}
+
+	return pitResp.PitID, nil
+}
+
+// closePIT releases a point-in-time id obtained via openPIT.
+func (c *OpenSearchClient) closePIT(ctx context.Context, pitID string) error {
+	reqBody, err := json.Marshal(map[string]string{"id": pitID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal pit close request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/_search/point_in_time", c.config.GetEndpoint())
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create pit close request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.UsesBasicAuth() {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to close pit: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("unexpected status code %d closing pit: %s", httpResp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// closeScroll releases a scroll context obtained via executeScroll.
+func (c *OpenSearchClient) closeScroll(ctx context.Context, scrollID string) error {
+	reqBody, err := json.Marshal(map[string]string{"scroll_id": scrollID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal scroll close request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/_search/scroll", c.config.GetEndpoint())
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create scroll close request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.UsesBasicAuth() {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to close scroll: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("unexpected status code %d closing scroll: %s", httpResp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// hitSortValues extracts the sort values attached to a hit, used as the next
+// page's search_after.
+func hitSortValues(hit Hit) []interface{} {
+	return hit.Sort
+}
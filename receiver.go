@@ -6,7 +6,7 @@ package opensearchqueryreceiver
 // that queries OpenSearch indices and converts the results into metrics.
 //
 // Key Features:
-// - Dual operational modes: direct and proxy
+// - Four operational modes: direct, proxy, oauth2, and aws_sigv4
 // - Support for complex OpenSearch queries with aggregations
 // - Automatic time-range filtering based on lookback period
 // - Conversion of query results to OpenTelemetry metrics
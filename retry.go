@@ -0,0 +1,343 @@
+package opensearchqueryreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ErrCircuitOpen is returned by retryTransport when a request is
+// short-circuited because the breaker for its endpoint is open. Callers can
+// match on it to emit a distinct self-observability signal (e.g. an
+// opensearch.query.circuit_open metric) instead of a generic error.
+var ErrCircuitOpen = errors.New("opensearchqueryreceiver: circuit breaker open")
+
+// breakerState is the state of a single endpoint's circuit breaker.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker tracks consecutive failures for one endpoint and opens
+// after BreakerThreshold of them, refusing further requests until
+// BreakerCooldown has elapsed, at which point a single probe request is
+// allowed through (half-open).
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	threshold int
+	cooldown  time.Duration
+
+	state           breakerState
+	consecutiveFail int
+	openedAt        time.Time
+}
+
+func newCircuitBreaker(cfg RetryConfig) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: cfg.BreakerThreshold,
+		cooldown:  cfg.BreakerCooldown,
+		state:     breakerClosed,
+	}
+}
+
+// allow reports whether a request may proceed, transitioning an open
+// breaker to half-open once the cooldown has elapsed. Only the single
+// request that performs that transition is let through as the probe;
+// concurrent callers arriving while the probe is still in flight are
+// refused, since letting them all through would defeat the point of
+// bounding the blast radius of a probe against a still-failing backend.
+// recordSuccess/recordFailure always resolve the probe (to closed or back
+// to open), so half-open can never wedge shut.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) >= b.cooldown {
+			b.state = breakerHalfOpen
+			return true
+		}
+		return false
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFail = 0
+	b.state = breakerClosed
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFail++
+	if b.state == breakerHalfOpen || b.consecutiveFail >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// retryTransport wraps an http.RoundTripper with exponential backoff plus
+// jitter and a per-endpoint circuit breaker. Only idempotent failures are
+// retried: network errors, 429/502/503/504 responses (honoring Retry-After
+// when the server supplies one), and responses of any HTTP status carrying a
+// search_phase_execution_exception whose inner status looks like a 429.
+type retryTransport struct {
+	base   http.RoundTripper
+	config RetryConfig
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+
+	telemetry *retryTelemetry
+}
+
+// retryTelemetry holds the internal self-observability instruments exposed
+// by the retry transport: a retries counter and a per-endpoint breaker-state
+// gauge, so operators can alert on a cluster the receiver is struggling to
+// reach without having to infer it from scrape gaps.
+type retryTelemetry struct {
+	retriesTotal metric.Int64Counter
+}
+
+func newRetryTransport(base http.RoundTripper, cfg RetryConfig) *retryTransport {
+	return &retryTransport{
+		base:     base,
+		config:   cfg,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// configureTelemetry wires the transport's self-observability instruments to
+// meter. It's optional: a transport never given a meter simply records
+// nothing. The breaker-state gauge observes every known endpoint's breaker
+// each collection, so it reflects state even for endpoints that haven't seen
+// a request since the last observation.
+func (t *retryTransport) configureTelemetry(meter metric.Meter) error {
+	retriesTotal, err := meter.Int64Counter(
+		"otelcol_receiver_opensearchquery_retries_total",
+		metric.WithDescription("Number of HTTP requests retried against OpenSearch"),
+		metric.WithUnit("{retry}"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create retries_total counter: %w", err)
+	}
+
+	_, err = meter.Int64ObservableGauge(
+		"otelcol_receiver_opensearchquery_breaker_state",
+		metric.WithDescription("Circuit breaker state per endpoint (0=closed, 1=open, 2=half_open)"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			for endpoint, b := range t.breakers {
+				b.mu.Lock()
+				state := int64(b.state)
+				b.mu.Unlock()
+				o.Observe(state, metric.WithAttributes(attribute.String("endpoint", endpoint)))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create breaker_state gauge: %w", err)
+	}
+
+	t.telemetry = &retryTelemetry{retriesTotal: retriesTotal}
+	return nil
+}
+
+func (t *retryTransport) breakerFor(req *http.Request) *circuitBreaker {
+	key := req.URL.Host + req.URL.Path
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.breakers[key]
+	if !ok {
+		b = newCircuitBreaker(t.config)
+		t.breakers[key] = b
+	}
+	return b
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.config.Enabled {
+		return t.base.RoundTrip(req)
+	}
+
+	breaker := t.breakerFor(req)
+	if !breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	var (
+		bodyBytes []byte
+		err       error
+	)
+	if req.Body != nil {
+		bodyBytes, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body for retry: %w", err)
+		}
+		req.Body.Close()
+	}
+
+	start := time.Now()
+	attempt := 0
+
+	for {
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, roundTripErr := t.base.RoundTrip(req)
+
+		var respBody []byte
+		if roundTripErr == nil {
+			respBody, err = io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to buffer response body for retry: %w", err)
+			}
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		}
+
+		retryable := roundTripErr != nil ||
+			isRetryableStatus(resp.StatusCode) ||
+			isRetryableBody(respBody)
+		if !retryable {
+			breaker.recordSuccess()
+			return resp, roundTripErr
+		}
+
+		breaker.recordFailure()
+
+		if t.config.MaxRetries > 0 && attempt >= t.config.MaxRetries {
+			if roundTripErr != nil {
+				return nil, fmt.Errorf("giving up after %d retries: %w", attempt, roundTripErr)
+			}
+			return resp, nil
+		}
+
+		if t.config.MaxElapsedTime > 0 && time.Since(start) >= t.config.MaxElapsedTime {
+			if roundTripErr != nil {
+				return nil, fmt.Errorf("giving up after %v: %w", time.Since(start), roundTripErr)
+			}
+			return resp, nil
+		}
+
+		if t.telemetry != nil {
+			t.telemetry.retriesTotal.Add(req.Context(), 1, metric.WithAttributes(attribute.String("endpoint", req.URL.Host)))
+		}
+
+		wait := t.backoffDelay(attempt)
+		if roundTripErr == nil {
+			if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > 0 {
+				wait = retryAfter
+			}
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		attempt++
+	}
+}
+
+// backoffDelay computes an exponential backoff with full jitter:
+// sleep = rand(0, min(cap, base * 2^attempt)).
+func (t *retryTransport) backoffDelay(attempt int) time.Duration {
+	maxInterval := t.config.MaxInterval
+	base := t.config.InitialInterval
+
+	maxDelay := base * time.Duration(1<<uint(attempt))
+	if maxDelay <= 0 || maxDelay > maxInterval {
+		maxDelay = maxInterval
+	}
+
+	return time.Duration(rand.Int63n(int64(maxDelay) + 1))
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// openSearchErrorBody is the shape of an OpenSearch error response, enough
+// of it to recognize a search_phase_execution_exception caused by a
+// throttled shard (an inner status of 429) even when the outer HTTP status
+// isn't itself one of the retryable ones.
+type openSearchErrorBody struct {
+	Error struct {
+		Type     string `json:"type"`
+		CausedBy struct {
+			Type   string `json:"type"`
+			Status int    `json:"status"`
+		} `json:"caused_by"`
+	} `json:"error"`
+	Status int `json:"status"`
+}
+
+// isRetryableBody reports whether body is a search_phase_execution_exception
+// with a 429-like inner status, which OpenSearch can return with an outer
+// HTTP status that isRetryableStatus wouldn't otherwise catch.
+func isRetryableBody(body []byte) bool {
+	if len(body) == 0 {
+		return false
+	}
+
+	var parsed openSearchErrorBody
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return false
+	}
+
+	if parsed.Error.Type != "search_phase_execution_exception" {
+		return false
+	}
+
+	return parsed.Status == http.StatusTooManyRequests || parsed.Error.CausedBy.Status == http.StatusTooManyRequests
+}
+
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
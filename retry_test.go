@@ -0,0 +1,166 @@
+package opensearchqueryreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryTransportRetriesOnServiceUnavailable(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := RetryConfig{
+		Enabled:          true,
+		InitialInterval:  time.Millisecond,
+		MaxInterval:      10 * time.Millisecond,
+		MaxElapsedTime:   time.Second,
+		BreakerThreshold: 10,
+		BreakerCooldown:  time.Second,
+	}
+
+	transport := newRetryTransport(http.DefaultTransport, cfg)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected eventual 200, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	cfg := RetryConfig{
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	}
+	b := newCircuitBreaker(cfg)
+
+	if !b.allow() {
+		t.Fatal("expected breaker to allow requests initially")
+	}
+
+	b.recordFailure()
+	if !b.allow() {
+		t.Fatal("expected breaker to still allow requests below threshold")
+	}
+
+	b.recordFailure()
+	if b.allow() {
+		t.Fatal("expected breaker to be open after reaching threshold")
+	}
+}
+
+func TestCircuitBreakerHalfOpenAllowsOnlyOneConcurrentProbe(t *testing.T) {
+	cfg := RetryConfig{
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Millisecond,
+	}
+	b := newCircuitBreaker(cfg)
+
+	b.recordFailure()
+	if b.allow() {
+		t.Fatal("expected breaker to be open immediately after tripping")
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("expected the first caller after cooldown to be let through as the probe")
+	}
+	if b.allow() {
+		t.Error("expected a second concurrent caller to be refused while the probe is in flight")
+	}
+	if b.allow() {
+		t.Error("expected a third concurrent caller to be refused while the probe is in flight")
+	}
+
+	b.recordSuccess()
+	if !b.allow() {
+		t.Fatal("expected the breaker to allow requests again once the probe succeeded")
+	}
+}
+
+func TestRetryTransportGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := RetryConfig{
+		Enabled:          true,
+		InitialInterval:  time.Millisecond,
+		MaxInterval:      10 * time.Millisecond,
+		MaxRetries:       2,
+		BreakerThreshold: 10,
+		BreakerCooldown:  time.Second,
+	}
+
+	transport := newRetryTransport(http.DefaultTransport, cfg)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts != 3 { // initial attempt + 2 retries
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestIsRetryableBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{
+			name: "search_phase_execution_exception with 429 status",
+			body: `{"error":{"type":"search_phase_execution_exception"},"status":429}`,
+			want: true,
+		},
+		{
+			name: "search_phase_execution_exception with 429 caused_by",
+			body: `{"error":{"type":"search_phase_execution_exception","caused_by":{"type":"circuit_breaking_exception","status":429}},"status":400}`,
+			want: true,
+		},
+		{
+			name: "unrelated exception type",
+			body: `{"error":{"type":"index_not_found_exception"},"status":404}`,
+			want: false,
+		},
+		{
+			name: "empty body",
+			body: "",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("isRetryableBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package opensearchqueryreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -10,14 +12,32 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.uber.org/zap"
+
+	"github.com/opensearchqueryreceiver/internal/metadata"
 )
 
-// scraper queries OpenSearch and converts results to OTel metrics
+// scraper queries OpenSearch and converts results to OTel metrics. Each
+// configured query runs on its own ticker (its CollectionInterval, or the
+// receiver's global one if unset) so a fast error-count query and a slow
+// capacity-planning query in the same receiver don't share a cadence.
+// Results accumulate in results as each query completes and are drained by
+// scrape(), which the receiver calls on the global CollectionInterval. A
+// query whose own interval is shorter than the global one simply accumulates
+// several results between scrapes instead of blocking or losing ticks, which
+// a fixed-capacity channel drained only once per global interval could not
+// guarantee.
 type scraper struct {
 	config   *Config
 	client   *OpenSearchClient
 	logger   *zap.Logger
 	settings receiver.Settings
+	status   *statusReporter
+
+	resultsMu sync.Mutex
+	results   []pmetric.Metrics
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // newScraperInstance creates a new scraper instance
@@ -33,11 +53,19 @@ func newScraperInstance(cfg *Config, settings receiver.Settings) (*scraper, erro
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
+	if meterProvider := settings.TelemetrySettings.MeterProvider; meterProvider != nil {
+		meter := meterProvider.Meter(typeStr.String())
+		if err := client.ConfigureTelemetry(meter); err != nil {
+			settings.Logger.Warn("Failed to configure retry telemetry", zap.Error(err))
+		}
+	}
+
 	return &scraper{
 		config:   cfg,
 		client:   client,
 		logger:   settings.Logger,
 		settings: settings,
+		status:   newStatusReporter(settings),
 	}, nil
 }
 
@@ -50,51 +78,149 @@ func (s *scraper) start(ctx context.Context, host component.Host) error {
 		zap.Int("num_queries", len(s.config.Queries)),
 	)
 
+	if err := s.client.ConfigureAuth(host); err != nil {
+		return fmt.Errorf("failed to configure auth extension: %w", err)
+	}
+
 	// Ping OpenSearch to verify connectivity
 	if err := s.client.Ping(ctx); err != nil {
 		s.logger.Warn("Failed to ping OpenSearch on startup", zap.Error(err))
 		// Don't fail startup, as OpenSearch might be temporarily unavailable
 	}
 
+	loopCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.client.StartNodePool(loopCtx)
+
+	for _, queryConfig := range s.config.Queries {
+		s.wg.Add(1)
+		go s.runQueryLoop(loopCtx, queryConfig)
+	}
+
 	return nil
 }
 
 // shutdown is called when the receiver stops
 func (s *scraper) shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down OpenSearch Query Receiver")
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
 	return nil
 }
 
-// scrape executes all configured queries and returns metrics
-func (s *scraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+// runQueryLoop ticks a single query on its own interval, independent of the
+// other configured queries, and accumulates its results onto results.
+func (s *scraper) runQueryLoop(ctx context.Context, queryConfig QueryConfig) {
+	defer s.wg.Done()
+
+	interval := queryConfig.CollectionInterval
+	if interval <= 0 {
+		interval = s.config.CollectionInterval
+	}
+	initialDelay := queryConfig.InitialDelay
+	if initialDelay <= 0 {
+		initialDelay = s.config.InitialDelay
+	}
+
+	if initialDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(initialDelay):
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.collectQuery(ctx, queryConfig)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.collectQuery(ctx, queryConfig)
+		}
+	}
+}
+
+// collectQuery executes a single query and accumulates its resulting
+// metrics, tagged with its own receiver.alias resource attribute, onto results.
+func (s *scraper) collectQuery(ctx context.Context, queryConfig QueryConfig) {
 	now := pcommon.NewTimestampFromTime(time.Now())
+	metrics, sm := s.newResourceMetrics(queryConfig)
+	mb := metadata.NewMetricsBuilder(s.config.MetricsBuilderConfig)
+
+	if err := s.executeAndRecordQuery(ctx, queryConfig, sm, mb, now); err != nil {
+		circuitOpen := errors.Is(err, ErrCircuitOpen)
+		mb.RecordOpensearchQueryCircuitOpenDataPoint(now, boolToInt64(circuitOpen), queryConfig.Name)
+
+		s.logger.Error("Failed to execute query",
+			zap.String("query_name", queryConfig.Name),
+			zap.Bool("circuit_open", circuitOpen),
+			zap.Error(err),
+		)
+		s.status.reportFailure(queryConfig.Name, err)
+	} else {
+		s.status.reportSuccess(queryConfig.Name)
+	}
+
+	mb.MoveTo(sm)
+
+	s.resultsMu.Lock()
+	s.results = append(s.results, metrics)
+	s.resultsMu.Unlock()
+}
+
+// newResourceMetrics builds a fresh pmetric.Metrics with one resource scoped
+// to queryConfig, tagged with a receiver.alias attribute (Alias, falling
+// back to the query name) so downstream pipelines can tell queries apart.
+func (s *scraper) newResourceMetrics(queryConfig QueryConfig) (pmetric.Metrics, pmetric.ScopeMetrics) {
 	metrics := pmetric.NewMetrics()
 
-	// Create resource metrics
+	rb := metadata.NewResourceBuilder(s.config.MetricsBuilderConfig.ResourceAttributes)
+	rb.SetOpensearchEndpoint(s.config.GetEndpoint())
+	rb.SetOpensearchIndexPattern(s.config.IndexPattern)
+	rb.SetOpensearchMode(s.config.Mode)
+
+	alias := queryConfig.Alias
+	if alias == "" {
+		alias = queryConfig.Name
+	}
+	rb.SetReceiverAlias(alias)
+
 	rm := metrics.ResourceMetrics().AppendEmpty()
-	resourceAttrs := rm.Resource().Attributes()
-	resourceAttrs.PutStr("receiver", typeStr.String())
-	resourceAttrs.PutStr("opensearch.endpoint", s.config.GetEndpoint())
-	resourceAttrs.PutStr("opensearch.index_pattern", s.config.IndexPattern)
-	resourceAttrs.PutStr("opensearch.mode", s.config.Mode)
+	rb.Emit().CopyTo(rm.Resource())
+	rm.Resource().Attributes().PutStr("receiver", typeStr.String())
 
-	// Create scope metrics
 	sm := rm.ScopeMetrics().AppendEmpty()
 	sm.Scope().SetName(typeStr.String())
 
-	// Execute each configured query
-	for _, queryConfig := range s.config.Queries {
-		if err := s.executeAndRecordQuery(ctx, queryConfig, sm, now); err != nil {
-			s.logger.Error("Failed to execute query",
-				zap.String("query_name", queryConfig.Name),
-				zap.Error(err),
-			)
-			// Continue with other queries even if one fails
-			continue
-		}
-	}
+	return metrics, sm
+}
 
-	return metrics, nil
+// scrape drains whatever query results have accumulated in results since
+// the last call and merges them into a single pmetric.Metrics. Query
+// execution itself runs continuously on each query's own ticker (see
+// runQueryLoop); scrape only flushes what's ready when the receiver's
+// global CollectionInterval ticks.
+func (s *scraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	s.resultsMu.Lock()
+	results := s.results
+	s.results = nil
+	s.resultsMu.Unlock()
+
+	merged := pmetric.NewMetrics()
+	for _, m := range results {
+		m.ResourceMetrics().MoveAndAppendTo(merged.ResourceMetrics())
+	}
+	return merged, nil
 }
 
 // executeAndRecordQuery executes a single query and records the results as metrics
@@ -102,87 +228,101 @@ func (s *scraper) executeAndRecordQuery(
 	ctx context.Context,
 	queryConfig QueryConfig,
 	sm pmetric.ScopeMetrics,
+	mb *metadata.MetricsBuilder,
 	timestamp pcommon.Timestamp,
 ) error {
+	if queryConfig.Language == "sql" || queryConfig.Language == "ppl" {
+		return s.executeAndRecordTabularQuery(ctx, queryConfig, sm, timestamp)
+	}
+
+	if queryConfig.Language == "lucene" || queryConfig.Language == "promql" || queryConfig.Language == "logql" {
+		return s.executeAndRecordTranslatedQuery(ctx, queryConfig, sm, timestamp)
+	}
+
+	if queryConfig.Pagination != nil {
+		return s.executeAndRecordPaginatedQuery(ctx, queryConfig, mb, timestamp)
+	}
+
 	// Execute query
 	resp, err := s.client.ExecuteQuery(ctx, queryConfig)
 	if err != nil {
 		return fmt.Errorf("query execution failed: %w", err)
 	}
 
-	// Determine metric name
+	mb.RecordOpensearchQueryResultCountDataPoint(timestamp, resp.Hits.Total.Value, queryConfig.Name)
+	mb.RecordOpensearchQueryTookDataPoint(timestamp, int64(resp.Took), queryConfig.Name)
+	mb.RecordOpensearchQueryShardsDataPoint(timestamp, int64(resp.Shards.Total), queryConfig.Name, "total")
+	mb.RecordOpensearchQueryShardsDataPoint(timestamp, int64(resp.Shards.Successful), queryConfig.Name, "successful")
+	mb.RecordOpensearchQueryShardsDataPoint(timestamp, int64(resp.Shards.Failed), queryConfig.Name, "failed")
+
+	// Determine the base name for the dynamic aggregation/extractor metrics
+	// below, which fall outside the generated catalog (see metadata.yaml).
 	metricName := queryConfig.MetricName
 	if metricName == "" {
 		metricName = fmt.Sprintf("opensearch.query.%s", queryConfig.Name)
 	}
 
-	// Record hit count as a gauge metric
-	s.recordGaugeMetric(
-		sm,
-		fmt.Sprintf("%s.count", metricName),
-		"Number of documents matching the query",
-		"hits",
-		float64(resp.Hits.Total.Value),
-		queryConfig,
-		timestamp,
-	)
-
-	// Record query execution time
-	s.recordGaugeMetric(
-		sm,
-		fmt.Sprintf("%s.took_ms", metricName),
-		"Query execution time in milliseconds",
-		"ms",
-		float64(resp.Took),
-		queryConfig,
-		timestamp,
-	)
+	// Process aggregations: explicit metric extractors take precedence over
+	// the generic auto-detected conversion below.
+	if len(queryConfig.Metrics) > 0 {
+		s.applyMetricExtractors(sm, resp, queryConfig, timestamp)
+	} else if resp.Aggregations != nil && len(resp.Aggregations) > 0 {
+		s.processAggregations(sm, metricName, resp.Aggregations, queryConfig, timestamp)
+	}
 
-	// Record shard statistics
-	s.recordGaugeMetric(
-		sm,
-		fmt.Sprintf("%s.shards.total", metricName),
-		"Total number of shards queried",
-		"shards",
-		float64(resp.Shards.Total),
-		queryConfig,
-		timestamp,
+	s.logger.Debug("Query metrics recorded",
+		zap.String("query_name", queryConfig.Name),
+		zap.Int64("hit_count", resp.Hits.Total.Value),
+		zap.Int("took_ms", resp.Took),
 	)
 
-	s.recordGaugeMetric(
-		sm,
-		fmt.Sprintf("%s.shards.successful", metricName),
-		"Number of successful shards",
-		"shards",
-		float64(resp.Shards.Successful),
-		queryConfig,
-		timestamp,
-	)
+	return nil
+}
 
-	s.recordGaugeMetric(
-		sm,
-		fmt.Sprintf("%s.shards.failed", metricName),
-		"Number of failed shards",
-		"shards",
-		float64(resp.Shards.Failed),
-		queryConfig,
-		timestamp,
-	)
+// executeAndRecordPaginatedQuery drives a query through its configured
+// pagination strategy, streaming hits rather than buffering them, and
+// records the total number of hits seen via the generated
+// opensearch.query.result_count metric, plus the pagination cycle's own
+// pages_fetched (and, for the "pit" strategy, pit_open_duration) metrics.
+func (s *scraper) executeAndRecordPaginatedQuery(
+	ctx context.Context,
+	queryConfig QueryConfig,
+	mb *metadata.MetricsBuilder,
+	timestamp pcommon.Timestamp,
+) error {
+	var hitCount int64
+	stats, err := s.client.ExecutePaginatedQuery(ctx, queryConfig, func(hit Hit) error {
+		hitCount++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("paginated query execution failed: %w", err)
+	}
 
-	// Process aggregations if present
-	if resp.Aggregations != nil && len(resp.Aggregations) > 0 {
-		s.processAggregations(sm, metricName, resp.Aggregations, queryConfig, timestamp)
+	mb.RecordOpensearchQueryResultCountDataPoint(timestamp, hitCount, queryConfig.Name)
+	mb.RecordOpensearchQueryPaginationPagesFetchedDataPoint(timestamp, int64(stats.PagesFetched), queryConfig.Name)
+	if queryConfig.Pagination.Strategy == "pit" {
+		mb.RecordOpensearchQueryPaginationPitOpenDurationDataPoint(timestamp, stats.PITOpenDuration.Milliseconds(), queryConfig.Name)
 	}
 
-	s.logger.Debug("Query metrics recorded",
+	s.logger.Debug("Paginated query metrics recorded",
 		zap.String("query_name", queryConfig.Name),
-		zap.Int64("hit_count", resp.Hits.Total.Value),
-		zap.Int("took_ms", resp.Took),
+		zap.Int64("hit_count", hitCount),
+		zap.Int("pages_fetched", stats.PagesFetched),
 	)
 
 	return nil
 }
 
+// boolToInt64 converts a bool to the 0/1 int64 used by the generated
+// circuit_open gauge metric.
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 // recordGaugeMetric creates and records a gauge metric
 func (s *scraper) recordGaugeMetric(
 	sm pmetric.ScopeMetrics,
@@ -243,6 +383,28 @@ func (s *scraper) processAggregation(
 		return
 	}
 
+	shape := detectAggregationShape(aggName, aggMap, queryConfig)
+	switch shape {
+	case aggShapeStats, aggShapeExtendedStats:
+		s.processStatsAggregation(sm, baseMetricName, aggName, aggMap, queryConfig, timestamp)
+		return
+	case aggShapePercentiles, aggShapePercentileRank:
+		s.processPercentilesAggregation(sm, baseMetricName, aggName, aggMap, queryConfig, timestamp)
+		return
+	case aggShapeCardinality:
+		s.processCardinalityAggregation(sm, baseMetricName, aggName, aggMap, queryConfig, timestamp)
+		return
+	case aggShapeTopHits:
+		s.processTopHitsAggregation(sm, baseMetricName, aggName, aggMap, queryConfig, timestamp)
+		return
+	case aggShapeFilters:
+		s.processFiltersAggregation(sm, baseMetricName, aggName, aggMap, queryConfig, timestamp)
+		return
+	case aggShapeDateHistogram, aggShapeHistogram:
+		s.processHistogramAggregation(sm, shape, baseMetricName, aggName, aggMap, queryConfig, timestamp)
+		return
+	}
+
 	// Handle value aggregations (avg, sum, min, max, etc.)
 	if value, ok := aggMap["value"].(float64); ok {
 		metricName := fmt.Sprintf("%s.agg.%s", baseMetricName, aggName)
@@ -0,0 +1,293 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+
+	"github.com/opensearchqueryreceiver/internal/metadata"
+)
+
+func TestApplyMetricExtractors(t *testing.T) {
+	tests := []struct {
+		name        string
+		resp        *SearchResponse
+		extractors  []MetricExtractorConfig
+		wantMetrics int
+	}{
+		{
+			name: "hits_total",
+			resp: &SearchResponse{
+				Hits: Hits{Total: HitsTotal{Value: 42}},
+			},
+			extractors: []MetricExtractorConfig{
+				{Type: "hits_total", Name: "opensearch.query.errors.count"},
+			},
+			wantMetrics: 1,
+		},
+		{
+			name: "terms bucket with nested stats sub-aggregation",
+			resp: &SearchResponse{
+				Aggregations: map[string]interface{}{
+					"by_region": map[string]interface{}{
+						"buckets": []interface{}{
+							map[string]interface{}{
+								"key":       "us-east-1",
+								"doc_count": float64(12),
+								"latency_stats": map[string]interface{}{
+									"count": float64(12),
+									"min":   float64(1),
+									"max":   float64(100),
+									"avg":   float64(20),
+									"sum":   float64(240),
+								},
+							},
+						},
+					},
+				},
+			},
+			extractors: []MetricExtractorConfig{
+				{Type: "terms_bucket", Name: "opensearch.query.region.count", Path: "by_region"},
+			},
+			wantMetrics: 1,
+		},
+		{
+			name: "date_histogram to percentiles",
+			resp: &SearchResponse{
+				Aggregations: map[string]interface{}{
+					"over_time": map[string]interface{}{
+						"buckets": []interface{}{
+							map[string]interface{}{
+								"key":           float64(1700000000000),
+								"key_as_string": "2023-11-14T22:13:20.000Z",
+								"doc_count":     float64(5),
+								"latency_percentiles": map[string]interface{}{
+									"values": map[string]interface{}{
+										"50.0": float64(10),
+										"99.0": float64(90),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			extractors: []MetricExtractorConfig{
+				{Type: "date_histogram", Name: "opensearch.query.over_time.count", Path: "over_time"},
+				{Type: "percentiles", Name: "opensearch.query.latency", Path: "over_time.latency_percentiles"},
+			},
+			wantMetrics: 3, // 1 date_histogram bucket + 2 percentile values
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &scraper{logger: zap.NewNop()}
+			metrics := pmetric.NewMetrics()
+			sm := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+			queryConfig := QueryConfig{Name: "test_query", Metrics: tt.extractors}
+
+			s.applyMetricExtractors(sm, tt.resp, queryConfig, pcommon.NewTimestampFromTime(time.Now()))
+
+			if got := sm.Metrics().Len(); got != tt.wantMetrics {
+				t.Errorf("applyMetricExtractors() emitted %d metrics, want %d", got, tt.wantMetrics)
+			}
+		})
+	}
+}
+
+func TestProcessAggregationShapes(t *testing.T) {
+	tests := []struct {
+		name        string
+		aggName     string
+		aggValue    interface{}
+		queryConfig QueryConfig
+		wantMetrics int
+	}{
+		{
+			name:    "extended_stats",
+			aggName: "latency_stats",
+			aggValue: map[string]interface{}{
+				"count": float64(10), "min": float64(1), "max": float64(50),
+				"avg": float64(20), "sum": float64(200), "std_deviation": float64(5),
+				"std_deviation_bounds": map[string]interface{}{"upper": float64(30), "lower": float64(10)},
+			},
+			wantMetrics: 8, // count/min/max/avg/sum/std_deviation + upper/lower bounds
+		},
+		{
+			name:    "stats",
+			aggName: "latency_stats",
+			aggValue: map[string]interface{}{
+				"count": float64(10), "min": float64(1), "max": float64(50),
+				"avg": float64(20), "sum": float64(200),
+			},
+			wantMetrics: 5, // count/min/max/avg/sum, no std_deviation and no bounds
+		},
+		{
+			name:    "percentiles",
+			aggName: "latency_percentiles",
+			aggValue: map[string]interface{}{
+				"values": map[string]interface{}{"50.0": float64(10), "99.0": float64(90)},
+			},
+			wantMetrics: 2,
+		},
+		{
+			name:        "cardinality via hint",
+			aggName:     "distinct_users",
+			aggValue:    map[string]interface{}{"value": float64(42)},
+			queryConfig: QueryConfig{AggregationTypes: map[string]string{"distinct_users": "cardinality"}},
+			wantMetrics: 1,
+		},
+		{
+			name:    "top_hits",
+			aggName: "latest",
+			aggValue: map[string]interface{}{
+				"hits": map[string]interface{}{"total": map[string]interface{}{"value": float64(3)}},
+			},
+			wantMetrics: 1,
+		},
+		{
+			name:    "filters",
+			aggName: "by_status",
+			aggValue: map[string]interface{}{
+				"buckets": map[string]interface{}{
+					"success": map[string]interface{}{"doc_count": float64(5)},
+					"failure": map[string]interface{}{"doc_count": float64(1)},
+				},
+			},
+			wantMetrics: 2,
+		},
+		{
+			name:    "date_histogram",
+			aggName: "over_time",
+			aggValue: map[string]interface{}{
+				"buckets": []interface{}{
+					map[string]interface{}{"key": float64(1700000000000), "key_as_string": "2023-11-14T22:13:20.000Z", "doc_count": float64(5)},
+					map[string]interface{}{"key": float64(1700003600000), "key_as_string": "2023-11-14T23:13:20.000Z", "doc_count": float64(7)},
+				},
+			},
+			wantMetrics: 2,
+		},
+		{
+			name:    "histogram",
+			aggName: "by_price",
+			aggValue: map[string]interface{}{
+				"buckets": []interface{}{
+					map[string]interface{}{"key": float64(10), "doc_count": float64(5)},
+					map[string]interface{}{"key": float64(20), "doc_count": float64(7)},
+				},
+			},
+			wantMetrics: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &scraper{logger: zap.NewNop()}
+			metrics := pmetric.NewMetrics()
+			sm := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+			queryConfig := tt.queryConfig
+			queryConfig.Name = "test_query"
+
+			s.processAggregation(sm, "opensearch.query.test", tt.aggName, tt.aggValue, queryConfig, pcommon.NewTimestampFromTime(time.Now()))
+
+			if got := sm.Metrics().Len(); got != tt.wantMetrics {
+				t.Errorf("processAggregation() emitted %d metrics, want %d", got, tt.wantMetrics)
+			}
+		})
+	}
+}
+
+// TestProcessHistogramAggregationKeepsCollectionTimestampForPlainHistogram
+// verifies that a plain numeric histogram (no key_as_string, so its "key" is
+// a bucket boundary like a price, not epoch millis) keeps the collection
+// timestamp on its data points rather than having its key misread as
+// epoch millis, which previously landed every data point near 1970-01-01.
+func TestProcessHistogramAggregationKeepsCollectionTimestampForPlainHistogram(t *testing.T) {
+	s := &scraper{logger: zap.NewNop()}
+	metrics := pmetric.NewMetrics()
+	sm := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	queryConfig := QueryConfig{Name: "test_query"}
+	collectionTime := pcommon.NewTimestampFromTime(time.Now())
+
+	aggValue := map[string]interface{}{
+		"buckets": []interface{}{
+			map[string]interface{}{"key": float64(10), "doc_count": float64(5)},
+		},
+	}
+
+	s.processAggregation(sm, "opensearch.query.test", "by_price", aggValue, queryConfig, collectionTime)
+
+	if got := sm.Metrics().Len(); got != 1 {
+		t.Fatalf("expected 1 metric, got %d", got)
+	}
+	dp := sm.Metrics().At(0).Gauge().DataPoints().At(0)
+	if dp.Timestamp() != collectionTime {
+		t.Errorf("expected plain histogram bucket to keep collection timestamp %v, got %v", collectionTime, dp.Timestamp())
+	}
+}
+
+// TestScraperAccumulatesResultsPastOldChannelCapacity verifies that
+// collectQuery no longer blocks once more results have accumulated than the
+// old fixed-capacity metricsCh channel could hold: a single query's own
+// fast CollectionInterval ticking several times between global scrape()
+// calls must not drop any of those results.
+func TestScraperAccumulatesResultsPastOldChannelCapacity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		ClientConfig: confighttp.ClientConfig{
+			Timeout: 5 * time.Second,
+		},
+		Mode:                 "direct",
+		Endpoints:            []string{server.URL},
+		IndexPattern:         "logs-*",
+		TimeField:            "@timestamp",
+		LookbackPeriod:       5 * time.Minute,
+		CollectionInterval:   time.Minute,
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Queries: []QueryConfig{
+			{Name: "fast_query", Query: map[string]interface{}{"match_all": map[string]interface{}{}}},
+		},
+	}
+
+	settings := receiver.Settings{
+		ID:                component.MustNewID("opensearchquery"),
+		TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()},
+	}
+	s, err := newScraperInstance(cfg, settings)
+	if err != nil {
+		t.Fatalf("newScraperInstance() failed: %v", err)
+	}
+	s.client.StartNodePool(context.Background())
+
+	const numCollections = 10 // well past the old channel's cap of len(Queries)+1 == 2
+	for i := 0; i < numCollections; i++ {
+		s.collectQuery(context.Background(), cfg.Queries[0])
+	}
+
+	merged, err := s.scrape(context.Background())
+	if err != nil {
+		t.Fatalf("scrape() failed: %v", err)
+	}
+	if got := merged.ResourceMetrics().Len(); got != numCollections {
+		t.Errorf("expected all %d collections to survive to scrape(), got %d", numCollections, got)
+	}
+
+	if empty, err := s.scrape(context.Background()); err != nil || empty.ResourceMetrics().Len() != 0 {
+		t.Errorf("expected scrape() to drain results, got %d resource metrics (err=%v)", empty.ResourceMetrics().Len(), err)
+	}
+}
@@ -0,0 +1,113 @@
+package opensearchqueryreceiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// SigV4Config configures AWS SigV4 request signing for AWS OpenSearch
+// Service ("es") and OpenSearch Serverless ("aoss").
+type SigV4Config struct {
+	// Region is the AWS region of the target domain/collection.
+	Region string `mapstructure:"region"`
+
+	// Service is the signing service name: "es" for OpenSearch Service, or
+	// "aoss" for OpenSearch Serverless. Defaults to "es".
+	Service string `mapstructure:"service"`
+
+	// AssumeRoleARN, if set, is assumed via STS before signing requests.
+	AssumeRoleARN string `mapstructure:"assume_role_arn"`
+
+	// ExternalID is passed to AssumeRole when AssumeRoleARN is set.
+	ExternalID string `mapstructure:"external_id"`
+}
+
+// validate checks that the SigV4 configuration is usable and fills in defaults.
+func (s *SigV4Config) validate() error {
+	if s.Region == "" {
+		return errors.New("sigv4.region must be specified")
+	}
+	if s.Service == "" {
+		s.Service = "es"
+	}
+	if s.Service != "es" && s.Service != "aoss" {
+		return fmt.Errorf("sigv4.service must be 'es' or 'aoss', got '%s'", s.Service)
+	}
+	return nil
+}
+
+// sigv4Transport signs every outgoing request with AWS SigV4 before handing
+// it to base, using the default AWS credentials chain (env, shared config,
+// IRSA/web identity, EC2/ECS instance metadata), optionally assuming a role first.
+type sigv4Transport struct {
+	base        http.RoundTripper
+	signer      *v4.Signer
+	credentials aws.CredentialsProvider
+	region      string
+	service     string
+}
+
+func newSigV4Transport(ctx context.Context, cfg SigV4Config, base http.RoundTripper) (http.RoundTripper, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS credentials: %w", err)
+	}
+
+	creds := awsCfg.Credentials
+	if cfg.AssumeRoleARN != "" {
+		stsClient := sts.NewFromConfig(awsCfg)
+		creds = stscreds.NewAssumeRoleProvider(stsClient, cfg.AssumeRoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if cfg.ExternalID != "" {
+				o.ExternalID = &cfg.ExternalID
+			}
+		})
+	}
+
+	return &sigv4Transport{
+		base:        base,
+		signer:      v4.NewSigner(),
+		credentials: creds,
+		region:      cfg.Region,
+		service:     cfg.Service,
+	}, nil
+}
+
+func (t *sigv4Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body for sigv4 signing: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(hash[:])
+
+	creds, err := t.credentials.Retrieve(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	if err := t.signer.SignHTTP(req.Context(), creds, req, payloadHash, t.service, t.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request with sigv4: %w", err)
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,148 @@
+package opensearchqueryreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// tabularResponse is the shared shape OpenSearch's SQL and PPL plugins
+// return: a column schema plus one row of values per datarow.
+type tabularResponse struct {
+	Schema   []tabularColumn `json:"schema"`
+	Datarows [][]interface{} `json:"datarows"`
+	Total    int             `json:"total"`
+	Size     int             `json:"size"`
+}
+
+// tabularColumn describes one column of a tabularResponse.
+type tabularColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ExecuteTabularQuery runs query.QueryText through the OpenSearch SQL or PPL
+// plugin, based on query.Language, and returns the resulting table.
+func (c *OpenSearchClient) ExecuteTabularQuery(ctx context.Context, query QueryConfig) (*tabularResponse, error) {
+	var path string
+	switch query.Language {
+	case "sql":
+		path = "/_plugins/_sql"
+	case "ppl":
+		path = "/_plugins/_ppl"
+	default:
+		return nil, fmt.Errorf("unsupported query language %q", query.Language)
+	}
+
+	reqBody, err := json.Marshal(map[string]string{"query": query.QueryText})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s query: %w", query.Language, err)
+	}
+
+	url := fmt.Sprintf("%s%s", c.config.GetEndpoint(), path)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", query.Language, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.UsesBasicAuth() {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s request: %w", query.Language, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", query.Language, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tabResp tabularResponse
+	if err := json.Unmarshal(body, &tabResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s response: %w", query.Language, err)
+	}
+
+	return &tabResp, nil
+}
+
+// executeAndRecordTabularQuery runs a "sql"/"ppl" query and converts its
+// tabular response to metrics: one data point per numeric column per row,
+// named "<metricName>.<column>", with that row's string columns attached as
+// attributes on every data point emitted for the row.
+func (s *scraper) executeAndRecordTabularQuery(
+	ctx context.Context,
+	queryConfig QueryConfig,
+	sm pmetric.ScopeMetrics,
+	timestamp pcommon.Timestamp,
+) error {
+	resp, err := s.client.ExecuteTabularQuery(ctx, queryConfig)
+	if err != nil {
+		return fmt.Errorf("%s query execution failed: %w", queryConfig.Language, err)
+	}
+
+	metricName := queryConfig.MetricName
+	if metricName == "" {
+		metricName = fmt.Sprintf("opensearch.query.%s", queryConfig.Name)
+	}
+
+	for _, row := range resp.Datarows {
+		rowAttrs := make(map[string]string)
+		for i, col := range resp.Schema {
+			if i >= len(row) {
+				continue
+			}
+			if str, ok := row[i].(string); ok {
+				rowAttrs[col.Name] = str
+			}
+		}
+
+		for i, col := range resp.Schema {
+			if i >= len(row) {
+				continue
+			}
+			value, ok := toFloat64(row[i])
+			if !ok {
+				continue
+			}
+
+			dp := s.newDataPoint(sm, fmt.Sprintf("%s.%s", metricName, col.Name), fmt.Sprintf("%s column from %s query", col.Name, queryConfig.Language), "", value, timestamp)
+			attrs := dp.Attributes()
+			attrs.PutStr("query.name", queryConfig.Name)
+			for k, v := range rowAttrs {
+				attrs.PutStr(k, v)
+			}
+			for k, v := range queryConfig.Labels {
+				attrs.PutStr(k, v)
+			}
+		}
+	}
+
+	s.logger.Debug("Tabular query metrics recorded",
+		zap.String("query_name", queryConfig.Name),
+		zap.String("language", queryConfig.Language),
+		zap.Int("rows", len(resp.Datarows)),
+	)
+
+	return nil
+}
+
+// toFloat64 converts a decoded JSON numeric value to float64. SQL/PPL
+// datarows values decode as float64 (JSON numbers) or, for some column
+// types, json.Number-like strings; only the float64 case is treated as numeric.
+func toFloat64(v interface{}) (float64, bool) {
+	value, ok := v.(float64)
+	return value, ok
+}
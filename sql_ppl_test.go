@@ -0,0 +1,147 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+func TestExecuteTabularQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		wantPath string
+	}{
+		{name: "sql", language: "sql", wantPath: "/_plugins/_sql"},
+		{name: "ppl", language: "ppl", wantPath: "/_plugins/_ppl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("expected path %s, got %s", tt.wantPath, r.URL.Path)
+				}
+
+				response := tabularResponse{
+					Schema: []tabularColumn{
+						{Name: "service", Type: "keyword"},
+						{Name: "error_count", Type: "long"},
+					},
+					Datarows: [][]interface{}{
+						{"checkout", float64(7)},
+						{"cart", float64(3)},
+					},
+					Total: 2,
+					Size:  2,
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			config := &Config{
+				ClientConfig: confighttp.ClientConfig{
+					Endpoint: server.URL,
+					Timeout:  30 * time.Second,
+				},
+				Mode:           "direct",
+				IndexPattern:   "logs-*",
+				TimeField:      "@timestamp",
+				LookbackPeriod: 5 * time.Minute,
+			}
+
+			client, err := NewOpenSearchClient(config, zap.NewNop())
+			if err != nil {
+				t.Fatalf("NewOpenSearchClient() failed: %v", err)
+			}
+
+			queryConfig := QueryConfig{
+				Name:      "errors_by_service",
+				Language:  tt.language,
+				QueryText: "source=logs-* | stats count() by service",
+			}
+
+			resp, err := client.ExecuteTabularQuery(context.Background(), queryConfig)
+			if err != nil {
+				t.Fatalf("ExecuteTabularQuery() failed: %v", err)
+			}
+
+			if len(resp.Datarows) != 2 {
+				t.Fatalf("expected 2 datarows, got %d", len(resp.Datarows))
+			}
+		})
+	}
+}
+
+func TestExecuteAndRecordTabularQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := tabularResponse{
+			Schema: []tabularColumn{
+				{Name: "service", Type: "keyword"},
+				{Name: "error_count", Type: "long"},
+			},
+			Datarows: [][]interface{}{
+				{"checkout", float64(7)},
+				{"cart", float64(3)},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	config := &Config{
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: server.URL,
+			Timeout:  30 * time.Second,
+		},
+		Mode:           "direct",
+		IndexPattern:   "logs-*",
+		TimeField:      "@timestamp",
+		LookbackPeriod: 5 * time.Minute,
+	}
+
+	client, err := NewOpenSearchClient(config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewOpenSearchClient() failed: %v", err)
+	}
+
+	s := &scraper{config: config, client: client, logger: zap.NewNop()}
+	metrics := pmetric.NewMetrics()
+	sm := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+
+	queryConfig := QueryConfig{
+		Name:      "errors_by_service",
+		Language:  "ppl",
+		QueryText: "source=logs-* | stats count() by service",
+	}
+
+	err = s.executeAndRecordTabularQuery(context.Background(), queryConfig, sm, pcommon.NewTimestampFromTime(time.Now()))
+	if err != nil {
+		t.Fatalf("executeAndRecordTabularQuery() failed: %v", err)
+	}
+
+	if got := sm.Metrics().Len(); got != 2 {
+		t.Errorf("expected 2 metrics (one numeric column x 2 rows), got %d", got)
+	}
+
+	metric := sm.Metrics().At(0)
+	if metric.Name() != "opensearch.query.errors_by_service.error_count" {
+		t.Errorf("unexpected metric name: %s", metric.Name())
+	}
+
+	dp := metric.Gauge().DataPoints().At(0)
+	if service, ok := dp.Attributes().Get("service"); !ok || service.Str() != "checkout" {
+		t.Errorf("expected service=checkout attribute, got %v", dp.Attributes().AsRaw())
+	}
+}
@@ -0,0 +1,91 @@
+package opensearchqueryreceiver
+
+import (
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+// permanentErrorStreak is the number of consecutive failures a single query
+// must accumulate before statusReporter escalates from a recoverable-error
+// event to a permanent-error one. A lower number would make a single
+// transient OpenSearch blip look like the receiver itself going down.
+const permanentErrorStreak = 5
+
+// statusReporter turns per-query execution outcomes into component status
+// events via receiver.Settings.TelemetrySettings.ReportStatus, so an operator
+// with the healthcheck v2 extension wired up sees which specific query is
+// failing rather than an opaque collection-level error.
+//
+// It tracks a consecutive-failure streak per query name: the first failure
+// after a success reports StatusRecoverableError, further consecutive
+// failures keep reporting StatusRecoverableError until the streak reaches
+// permanentErrorStreak, at which point it escalates to
+// StatusPermanentError. Any subsequent success resets the streak and
+// reports StatusOK.
+type statusReporter struct {
+	reportStatus component.StatusFunc
+	logger       *zap.Logger
+
+	mu      sync.Mutex
+	streaks map[string]int
+}
+
+func newStatusReporter(settings receiver.Settings) *statusReporter {
+	return &statusReporter{
+		reportStatus: settings.TelemetrySettings.ReportStatus,
+		logger:       settings.Logger,
+		streaks:      make(map[string]int),
+	}
+}
+
+// reportSuccess clears queryName's failure streak. It reports StatusOK only
+// when the query was previously failing, so a healthy query that never fails
+// doesn't spam an OK event on every collection.
+func (r *statusReporter) reportSuccess(queryName string) {
+	r.mu.Lock()
+	wasFailing := r.streaks[queryName] > 0
+	r.streaks[queryName] = 0
+	r.mu.Unlock()
+
+	if wasFailing {
+		r.emit(component.NewStatusEvent(component.StatusOK), queryName, nil)
+	}
+}
+
+// reportFailure records a failure for queryName and reports a recoverable or
+// permanent error event depending on the resulting consecutive-failure streak.
+func (r *statusReporter) reportFailure(queryName string, err error) {
+	r.mu.Lock()
+	r.streaks[queryName]++
+	streak := r.streaks[queryName]
+	r.mu.Unlock()
+
+	wrapped := fmt.Errorf("query %q: %w", queryName, err)
+
+	var ev *component.StatusEvent
+	if streak >= permanentErrorStreak {
+		ev = component.NewPermanentErrorEvent(wrapped)
+	} else {
+		ev = component.NewRecoverableErrorEvent(wrapped)
+	}
+
+	r.emit(ev, queryName, wrapped)
+}
+
+func (r *statusReporter) emit(ev *component.StatusEvent, queryName string, err error) {
+	if r.reportStatus == nil {
+		return
+	}
+
+	r.reportStatus(ev)
+
+	r.logger.Debug("Reported component status",
+		zap.String("query_name", queryName),
+		zap.Stringer("status", ev.Status()),
+		zap.Error(err),
+	)
+}
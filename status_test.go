@@ -0,0 +1,75 @@
+package opensearchqueryreceiver
+
+import (
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+func newTestStatusReporter(t *testing.T) (*statusReporter, *[]*component.StatusEvent) {
+	t.Helper()
+
+	var events []*component.StatusEvent
+	r := &statusReporter{
+		reportStatus: func(ev *component.StatusEvent) {
+			events = append(events, ev)
+		},
+		logger:  zap.NewNop(),
+		streaks: make(map[string]int),
+	}
+	return r, &events
+}
+
+func TestStatusReporterReportsRecoverableThenPermanentError(t *testing.T) {
+	r, events := newTestStatusReporter(t)
+
+	for i := 0; i < permanentErrorStreak-1; i++ {
+		r.reportFailure("errors_by_service", errors.New("boom"))
+	}
+	if len(*events) != permanentErrorStreak-1 {
+		t.Fatalf("expected %d events, got %d", permanentErrorStreak-1, len(*events))
+	}
+	for _, ev := range *events {
+		if ev.Status() != component.StatusRecoverableError {
+			t.Errorf("expected StatusRecoverableError before the streak threshold, got %v", ev.Status())
+		}
+	}
+
+	r.reportFailure("errors_by_service", errors.New("boom"))
+	last := (*events)[len(*events)-1]
+	if last.Status() != component.StatusPermanentError {
+		t.Errorf("expected StatusPermanentError at the streak threshold, got %v", last.Status())
+	}
+}
+
+func TestStatusReporterReportsOKOnRecoveryOnly(t *testing.T) {
+	r, events := newTestStatusReporter(t)
+
+	r.reportSuccess("errors_by_service")
+	if len(*events) != 0 {
+		t.Fatalf("expected no event for a success with no prior failures, got %d", len(*events))
+	}
+
+	r.reportFailure("errors_by_service", errors.New("boom"))
+	r.reportSuccess("errors_by_service")
+
+	if len(*events) != 2 {
+		t.Fatalf("expected a failure event followed by a recovery event, got %d", len(*events))
+	}
+	if (*events)[1].Status() != component.StatusOK {
+		t.Errorf("expected StatusOK on recovery, got %v", (*events)[1].Status())
+	}
+}
+
+func TestStatusReporterTracksStreaksPerQuery(t *testing.T) {
+	r, events := newTestStatusReporter(t)
+
+	r.reportFailure("query_a", errors.New("boom"))
+	r.reportSuccess("query_b")
+
+	if len(*events) != 1 {
+		t.Fatalf("expected only query_a's failure to emit an event, got %d", len(*events))
+	}
+}
@@ -0,0 +1,102 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// lookbackState is the per-query record persisted via lookbackStore.
+type lookbackState struct {
+	// EndTime is the end of the time range covered by the last successful
+	// collection of this query.
+	EndTime time.Time `json:"end_time"`
+
+	// Cursor is EndTime's UnixNano, persisted alongside it so a reader can
+	// detect a stale write (e.g. from an old receiver instance sharing the
+	// same storage extension) without having to parse EndTime first.
+	Cursor int64 `json:"cursor"`
+}
+
+// lookbackStore persists each emit_logs query's last-successful end_time via
+// an extension.StorageExtension, keyed by {receiver_id, query_name}, so the
+// logs scraper can resume across restarts and collector reloads instead of
+// always starting from now-LookbackPeriod. With no storage extension
+// configured, client is a storage.NewNopClient() and every Load reports "not
+// found", so callers don't need to special-case the unconfigured case.
+type lookbackStore struct {
+	client     storage.Client
+	receiverID component.ID
+}
+
+// configureLookbackStore resolves cfg.StorageID (if set) against host's
+// extensions and returns a lookbackStore backed by it.
+func configureLookbackStore(ctx context.Context, host component.Host, receiverID component.ID, cfg *Config) (*lookbackStore, error) {
+	client, err := toStorageClient(ctx, cfg.StorageID, host, receiverID, component.KindReceiver)
+	if err != nil {
+		return nil, err
+	}
+	return &lookbackStore{client: client, receiverID: receiverID}, nil
+}
+
+// toStorageClient resolves storageID against host's extensions and opens a
+// client scoped to this component, mirroring the collector's standard
+// extension.StorageExtension lookup pattern. A nil storageID yields a no-op
+// client rather than an error, so storage stays optional.
+func toStorageClient(ctx context.Context, storageID *component.ID, host component.Host, componentID component.ID, kind component.Kind) (storage.Client, error) {
+	if storageID == nil {
+		return storage.NewNopClient(), nil
+	}
+
+	ext, found := host.GetExtensions()[*storageID]
+	if !found {
+		return nil, fmt.Errorf("storage extension %q not found", storageID)
+	}
+
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %q does not implement storage.Extension", storageID)
+	}
+
+	return storageExt.GetClient(ctx, kind, componentID, "")
+}
+
+// key returns the storage key for a query's lookback state, scoped to this
+// receiver instance so multiple receiver instances sharing one storage
+// extension don't collide.
+func (s *lookbackStore) key(queryName string) string {
+	return fmt.Sprintf("lookback/%s/%s", s.receiverID.String(), queryName)
+}
+
+// Load returns the persisted end_time for queryName and true, or the zero
+// time and false if nothing has been persisted yet.
+func (s *lookbackStore) Load(ctx context.Context, queryName string) (time.Time, bool) {
+	raw, err := s.client.Get(ctx, s.key(queryName))
+	if err != nil || raw == nil {
+		return time.Time{}, false
+	}
+
+	var state lookbackState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return time.Time{}, false
+	}
+	return state.EndTime, true
+}
+
+// Save persists queryName's new end_time.
+func (s *lookbackStore) Save(ctx context.Context, queryName string, endTime time.Time) error {
+	raw, err := json.Marshal(lookbackState{EndTime: endTime, Cursor: endTime.UnixNano()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal lookback state for query %q: %w", queryName, err)
+	}
+	return s.client.Set(ctx, s.key(queryName), raw)
+}
+
+// Close releases the underlying storage client.
+func (s *lookbackStore) Close(ctx context.Context) error {
+	return s.client.Close(ctx)
+}
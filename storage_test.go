@@ -0,0 +1,90 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// fakeStorageClient is an in-memory storage.Client used to test lookbackStore
+// without depending on a real storage extension.
+type fakeStorageClient struct {
+	data map[string][]byte
+}
+
+func newFakeStorageClient() storage.Client {
+	return &fakeStorageClient{data: make(map[string][]byte)}
+}
+
+func (f *fakeStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	return f.data[key], nil
+}
+
+func (f *fakeStorageClient) Set(_ context.Context, key string, value []byte) error {
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakeStorageClient) Delete(_ context.Context, key string) error {
+	delete(f.data, key)
+	return nil
+}
+
+func (f *fakeStorageClient) Close(_ context.Context) error {
+	return nil
+}
+
+func (f *fakeStorageClient) Batch(_ context.Context, _ ...*storage.Operation) error {
+	return nil
+}
+
+func TestLookbackStoreLoadMissingReturnsNotFound(t *testing.T) {
+	store := &lookbackStore{client: newFakeStorageClient(), receiverID: component.MustNewID("opensearchquery")}
+
+	if _, ok := store.Load(context.Background(), "errors_by_service"); ok {
+		t.Fatal("expected no persisted state for a query that was never saved")
+	}
+}
+
+func TestLookbackStoreSaveThenLoadRoundTrips(t *testing.T) {
+	store := &lookbackStore{client: newFakeStorageClient(), receiverID: component.MustNewID("opensearchquery")}
+
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.Save(context.Background(), "errors_by_service", want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, ok := store.Load(context.Background(), "errors_by_service")
+	if !ok {
+		t.Fatal("expected persisted state after Save")
+	}
+	if !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLookbackStoreScopesKeysPerQuery(t *testing.T) {
+	store := &lookbackStore{client: newFakeStorageClient(), receiverID: component.MustNewID("opensearchquery")}
+
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	if err := store.Save(context.Background(), "query_a", first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Save(context.Background(), "query_b", second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gotA, _ := store.Load(context.Background(), "query_a")
+	gotB, _ := store.Load(context.Background(), "query_b")
+	if !gotA.Equal(first) {
+		t.Errorf("expected query_a's state to be unaffected by query_b, got %v", gotA)
+	}
+	if !gotB.Equal(second) {
+		t.Errorf("expected query_b's own state, got %v", gotB)
+	}
+}
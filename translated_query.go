@@ -0,0 +1,144 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/opensearchqueryreceiver/translator"
+)
+
+// executeAndRecordTranslatedQuery runs a "lucene", "promql", or "logql"
+// query by compiling it to OpenSearch DSL via the translator package, then
+// records the response the way the translation says to: either the query's
+// total hit count as a single gauge, or a date_histogram's buckets as one
+// gauge per bucket (see translator.Translation.Aggregation).
+func (s *scraper) executeAndRecordTranslatedQuery(
+	ctx context.Context,
+	queryConfig QueryConfig,
+	sm pmetric.ScopeMetrics,
+	timestamp pcommon.Timestamp,
+) error {
+	translation, err := s.translateQuery(queryConfig)
+	if err != nil {
+		return fmt.Errorf("%s query translation failed: %w", queryConfig.Language, err)
+	}
+
+	translated := queryConfig
+	translated.Query = translation.Query
+	translated.Aggs = translation.Aggs
+
+	resp, err := s.client.ExecuteQuery(ctx, translated)
+	if err != nil {
+		return fmt.Errorf("%s query execution failed: %w", queryConfig.Language, err)
+	}
+
+	switch translation.Aggregation {
+	case "rate", "count_over_time":
+		s.recordTranslationBuckets(sm, translation, resp, queryConfig, timestamp)
+	default:
+		s.recordTranslationCount(sm, translation, resp, queryConfig, timestamp)
+	}
+
+	s.logger.Debug("Translated query metrics recorded",
+		zap.String("query_name", queryConfig.Name),
+		zap.String("language", queryConfig.Language),
+		zap.String("metric_name", translation.MetricName),
+	)
+
+	return nil
+}
+
+// translateQuery dispatches queryConfig.QueryText to the translator
+// function matching its Language.
+func (s *scraper) translateQuery(queryConfig QueryConfig) (*translator.Translation, error) {
+	switch queryConfig.Language {
+	case "lucene":
+		return translator.TranslateLucene(queryConfig.QueryText)
+	case "promql":
+		return translator.TranslatePromQL(queryConfig.QueryText, s.config.FieldMappings, s.config.TimeField)
+	case "logql":
+		return translator.TranslateLogQL(queryConfig.QueryText, s.config.FieldMappings, s.config.TimeField)
+	default:
+		return nil, fmt.Errorf("unsupported query language %q", queryConfig.Language)
+	}
+}
+
+// recordTranslationCount records a translation's result as a single gauge
+// data point holding the query's total hit count.
+func (s *scraper) recordTranslationCount(
+	sm pmetric.ScopeMetrics,
+	translation *translator.Translation,
+	resp *SearchResponse,
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	description := fmt.Sprintf("Result count for %s query %q", queryConfig.Language, queryConfig.Name)
+	dp := s.newDataPoint(sm, translation.MetricName, description, "{hit}", float64(resp.Hits.Total.Value), timestamp)
+	s.attachTranslationAttrs(dp, translation, queryConfig)
+}
+
+// recordTranslationBuckets records a translation's date_histogram buckets
+// (translation.AggregationName) as one gauge data point per bucket, tagged
+// with that bucket's start time. "rate" divides each bucket's doc_count by
+// translation.RangeSeconds; "count_over_time" uses doc_count as-is.
+func (s *scraper) recordTranslationBuckets(
+	sm pmetric.ScopeMetrics,
+	translation *translator.Translation,
+	resp *SearchResponse,
+	queryConfig QueryConfig,
+	timestamp pcommon.Timestamp,
+) {
+	aggValue, ok := resp.Aggregations[translation.AggregationName]
+	if !ok {
+		return
+	}
+	aggMap, ok := aggValue.(map[string]interface{})
+	if !ok {
+		return
+	}
+	buckets, ok := aggMap["buckets"].([]interface{})
+	if !ok {
+		return
+	}
+
+	description := fmt.Sprintf("%s for %s query %q", translation.Aggregation, queryConfig.Language, queryConfig.Name)
+
+	for _, bucket := range buckets {
+		bucketMap, ok := bucket.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		docCount, ok := bucketMap["doc_count"].(float64)
+		if !ok {
+			continue
+		}
+
+		value := docCount
+		if translation.Aggregation == "rate" && translation.RangeSeconds > 0 {
+			value = docCount / translation.RangeSeconds
+		}
+
+		dp := s.newDataPoint(sm, translation.MetricName, description, "", value, timestamp)
+		s.attachTranslationAttrs(dp, translation, queryConfig)
+		if keyStr, ok := bucketMap["key_as_string"].(string); ok {
+			dp.Attributes().PutStr("bucket.start", keyStr)
+		}
+	}
+}
+
+// attachTranslationAttrs attaches query.name, the translation's extracted
+// label set, and the query's custom labels to dp.
+func (s *scraper) attachTranslationAttrs(dp pmetric.NumberDataPoint, translation *translator.Translation, queryConfig QueryConfig) {
+	attrs := dp.Attributes()
+	attrs.PutStr("query.name", queryConfig.Name)
+	for k, v := range translation.Labels {
+		attrs.PutStr(k, v)
+	}
+	for k, v := range queryConfig.Labels {
+		attrs.PutStr(k, v)
+	}
+}
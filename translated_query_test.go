@@ -0,0 +1,136 @@
+package opensearchqueryreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
+
+	"github.com/opensearchqueryreceiver/translator"
+)
+
+// TestTranslatedQueryRoundTripsThroughTimeRangeFilter verifies that a
+// translated promql/logql query comes out of the translator as a bare
+// clause, not a full search body, so that addTimeRangeFilter wraps it
+// exactly once instead of nesting it inside an extra "query" key that
+// OpenSearch would reject.
+func TestTranslatedQueryRoundTripsThroughTimeRangeFilter(t *testing.T) {
+	var captured SearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		ClientConfig: confighttp.ClientConfig{
+			Timeout: 5 * time.Second,
+		},
+		Mode:           "direct",
+		Endpoints:      []string{server.URL},
+		IndexPattern:   "logs-*",
+		TimeField:      "@timestamp",
+		LookbackPeriod: 5 * time.Minute,
+	}
+
+	client, err := NewOpenSearchClient(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewOpenSearchClient() failed: %v", err)
+	}
+
+	translation, err := translator.TranslatePromQL(`errors_total{service="checkout"}`, map[string]string{"service": "service.keyword"}, "@timestamp")
+	if err != nil {
+		t.Fatalf("TranslatePromQL() failed: %v", err)
+	}
+
+	query := QueryConfig{Name: "test", Query: translation.Query}
+	if _, err := client.ExecuteQuery(context.Background(), query); err != nil {
+		t.Fatalf("ExecuteQuery() failed: %v", err)
+	}
+
+	boolQuery, ok := captured.Query["bool"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the request body's query to be a single bool clause, got %v", captured.Query)
+	}
+	must, ok := boolQuery["must"].([]interface{})
+	if !ok || len(must) != 1 {
+		t.Fatalf("expected exactly one must clause (the translated query), got %v", boolQuery["must"])
+	}
+	if _, ok := must[0].(map[string]interface{})["bool"]; !ok {
+		t.Errorf("expected the translated bool clause to be nested once under must, not double-wrapped under an extra query key, got %v", must[0])
+	}
+	if _, ok := boolQuery["filter"]; !ok {
+		t.Errorf("expected a time range filter clause, got %v", boolQuery)
+	}
+}
+
+// TestTranslatedRateQueryAttachesAggsAtTopLevel verifies that a promql
+// rate() query's date_histogram aggregation is sent as a top-level sibling
+// of "query" in the search request body, not nested inside the query
+// clause (where the time-range bool wrapper would have buried it under
+// query.bool.must[0].aggs, and OpenSearch would reject or ignore it).
+func TestTranslatedRateQueryAttachesAggsAtTopLevel(t *testing.T) {
+	var captured map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"took":1,"timed_out":false,"hits":{"total":{"value":0,"relation":"eq"},"hits":[]},"aggregations":{"promql_rate_histogram":{"buckets":[]}}}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		ClientConfig: confighttp.ClientConfig{
+			Timeout: 5 * time.Second,
+		},
+		Mode:           "direct",
+		Endpoints:      []string{server.URL},
+		IndexPattern:   "logs-*",
+		TimeField:      "@timestamp",
+		LookbackPeriod: 5 * time.Minute,
+	}
+
+	client, err := NewOpenSearchClient(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewOpenSearchClient() failed: %v", err)
+	}
+
+	translation, err := translator.TranslatePromQL(`rate(errors_total{service="checkout"}[5m])`, map[string]string{"service": "service.keyword"}, "@timestamp")
+	if err != nil {
+		t.Fatalf("TranslatePromQL() failed: %v", err)
+	}
+
+	query := QueryConfig{Name: "test", Query: translation.Query, Aggs: translation.Aggs}
+	if _, err := client.ExecuteQuery(context.Background(), query); err != nil {
+		t.Fatalf("ExecuteQuery() failed: %v", err)
+	}
+
+	if _, ok := captured["aggs"].(map[string]interface{}); !ok {
+		t.Fatalf("expected a top-level \"aggs\" key in the request body, got %v", captured)
+	}
+
+	queryBody, ok := captured["query"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a top-level \"query\" key, got %v", captured)
+	}
+	boolQuery, ok := queryBody["bool"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the top-level query to be a bool clause, got %v", queryBody)
+	}
+	must, ok := boolQuery["must"].([]interface{})
+	if !ok || len(must) != 1 {
+		t.Fatalf("expected exactly one must clause, got %v", boolQuery["must"])
+	}
+	if _, hasAggs := must[0].(map[string]interface{})["aggs"]; hasAggs {
+		t.Errorf("expected aggs to stay out of the query clause entirely, got %v", must[0])
+	}
+}
@@ -0,0 +1,64 @@
+package translator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// logQLPattern matches the supported LogQL subset: a stream selector
+// ("{label=\"x\", label2=\"y\"}") followed by an optional single line
+// filter ("|= \"text\"").
+var logQLPattern = regexp.MustCompile(`^\s*\{([^}]*)\}\s*(?:\|=\s*"([^"]*)")?\s*$`)
+
+// logQLLabelPattern matches one label="value" pair inside a stream selector.
+var logQLLabelPattern = regexp.MustCompile(`(\w+)\s*=\s*"([^"]*)"`)
+
+// TranslateLogQL compiles a LogQL stream selector, with an optional `|=`
+// line filter, into an OpenSearch DSL query that counts matching documents.
+// fieldMappings translates LogQL label names to OpenSearch document fields;
+// messageField is the field the line filter is matched against.
+//
+// Supported subset: {label="x", ...} |= "text". Label regexp matchers
+// (=~, !~), negated equality (!=), and chained/multiple line filters
+// aren't supported.
+func TranslateLogQL(query string, fieldMappings map[string]string, messageField string) (*Translation, error) {
+	m := logQLPattern.FindStringSubmatch(query)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported logql query %q: expected {label=\"x\"} optionally followed by |= \"text\"", query)
+	}
+
+	labelsPart, filterText := m[1], m[2]
+
+	var must []interface{}
+	selLabels := make(map[string]string)
+	for _, pair := range logQLLabelPattern.FindAllStringSubmatch(labelsPart, -1) {
+		label, value := pair[1], pair[2]
+		field := lookupField(fieldMappings, label)
+		must = append(must, map[string]interface{}{"term": map[string]interface{}{field: value}})
+		selLabels[label] = value
+	}
+
+	if strings.TrimSpace(labelsPart) != "" && len(must) == 0 {
+		return nil, fmt.Errorf("logql stream selector %q did not match any label=\"value\" pairs", labelsPart)
+	}
+
+	if filterText != "" {
+		must = append(must, map[string]interface{}{
+			"match_phrase": map[string]interface{}{messageField: filterText},
+		})
+	}
+
+	if len(must) == 0 {
+		must = append(must, map[string]interface{}{"match_all": map[string]interface{}{}})
+	}
+
+	return &Translation{
+		Query: map[string]interface{}{
+			"bool": map[string]interface{}{"must": must},
+		},
+		MetricName:  "logql.count_over_time",
+		Labels:      selLabels,
+		Aggregation: "count",
+	}, nil
+}
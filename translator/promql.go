@@ -0,0 +1,145 @@
+package translator
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// TranslatePromQL compiles a PromQL instant-vector selector (optionally
+// wrapped in rate(...)) into OpenSearch DSL. fieldMappings translates
+// PromQL label names to the OpenSearch document fields they're stored
+// under; an unmapped label is used as the field name verbatim. timeField is
+// the document field rate() buckets against.
+//
+// Supported subset: a bare vector selector (foo{label="x"}), equality,
+// negated-equality, and regexp label matchers, and rate(foo{...}[5m]).
+// Anything else (binary expressions, subqueries, functions other than
+// rate) returns an error rather than a best-effort guess.
+func TranslatePromQL(query string, fieldMappings map[string]string, timeField string) (*Translation, error) {
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse promql query: %w", err)
+	}
+
+	switch e := expr.(type) {
+	case *parser.VectorSelector:
+		return translateVectorSelector(e, fieldMappings)
+	case *parser.Call:
+		return translateRateCall(e, fieldMappings, timeField)
+	default:
+		return nil, fmt.Errorf("unsupported promql expression type %T", expr)
+	}
+}
+
+func translateVectorSelector(sel *parser.VectorSelector, fieldMappings map[string]string) (*Translation, error) {
+	query, selLabels, err := matchersToQuery(sel.LabelMatchers, fieldMappings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Translation{
+		Query:       query,
+		MetricName:  sel.Name,
+		Labels:      selLabels,
+		Aggregation: "count",
+	}, nil
+}
+
+func translateRateCall(call *parser.Call, fieldMappings map[string]string, timeField string) (*Translation, error) {
+	if call.Func == nil || call.Func.Name != "rate" {
+		return nil, fmt.Errorf("unsupported promql function %q, only rate() is supported", callName(call))
+	}
+	if len(call.Args) != 1 {
+		return nil, fmt.Errorf("rate() expects exactly one argument")
+	}
+
+	matrixSel, ok := call.Args[0].(*parser.MatrixSelector)
+	if !ok {
+		return nil, fmt.Errorf("rate() argument must be a range vector, e.g. rate(foo[5m])")
+	}
+	vecSel, ok := matrixSel.VectorSelector.(*parser.VectorSelector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported rate() argument %T", matrixSel.VectorSelector)
+	}
+
+	query, selLabels, err := matchersToQuery(vecSel.LabelMatchers, fieldMappings)
+	if err != nil {
+		return nil, err
+	}
+
+	rangeSeconds := matrixSel.Range.Seconds()
+	const aggName = "promql_rate_histogram"
+	aggs := map[string]interface{}{
+		aggName: map[string]interface{}{
+			"date_histogram": map[string]interface{}{
+				"field":          timeField,
+				"fixed_interval": fmt.Sprintf("%ds", int(rangeSeconds)),
+			},
+		},
+	}
+
+	return &Translation{
+		Query:           query,
+		Aggs:            aggs,
+		MetricName:      vecSel.Name,
+		Labels:          selLabels,
+		Aggregation:     "rate",
+		AggregationName: aggName,
+		RangeSeconds:    rangeSeconds,
+	}, nil
+}
+
+func callName(call *parser.Call) string {
+	if call.Func == nil {
+		return "<unknown>"
+	}
+	return call.Func.Name
+}
+
+// matchersToQuery converts PromQL label matchers into an OpenSearch
+// bool query: equality and regexp matchers become "must" clauses, negated
+// matchers become "must_not" clauses. The metric name matcher (__name__,
+// added implicitly by the parser for "foo{...}") is skipped since it's
+// already captured by Translation.MetricName. Equality matchers are also
+// returned as a label set to attach to every emitted data point.
+func matchersToQuery(matchers []*labels.Matcher, fieldMappings map[string]string) (map[string]interface{}, map[string]string, error) {
+	var must, mustNot []interface{}
+	selLabels := make(map[string]string)
+
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			continue
+		}
+		field := lookupField(fieldMappings, m.Name)
+
+		switch m.Type {
+		case labels.MatchEqual:
+			must = append(must, map[string]interface{}{"term": map[string]interface{}{field: m.Value}})
+			selLabels[m.Name] = m.Value
+		case labels.MatchNotEqual:
+			mustNot = append(mustNot, map[string]interface{}{"term": map[string]interface{}{field: m.Value}})
+		case labels.MatchRegexp:
+			must = append(must, map[string]interface{}{"regexp": map[string]interface{}{field: m.Value}})
+		case labels.MatchNotRegexp:
+			mustNot = append(mustNot, map[string]interface{}{"regexp": map[string]interface{}{field: m.Value}})
+		default:
+			return nil, nil, fmt.Errorf("unsupported label matcher type for %q", m.Name)
+		}
+	}
+
+	boolQuery := map[string]interface{}{}
+	if len(must) > 0 {
+		boolQuery["must"] = must
+	} else {
+		boolQuery["must"] = []interface{}{map[string]interface{}{"match_all": map[string]interface{}{}}}
+	}
+	if len(mustNot) > 0 {
+		boolQuery["must_not"] = mustNot
+	}
+
+	return map[string]interface{}{
+		"bool": boolQuery,
+	}, selLabels, nil
+}
@@ -0,0 +1,78 @@
+// Package translator compiles the portable query surfaces accepted by
+// QueryConfig.Language ("lucene", "promql", "logql") into OpenSearch query
+// DSL, so users can express a query once instead of hand-authoring DSL JSON
+// per target. It intentionally covers a pragmatic subset of each language
+// rather than full compatibility: enough to select documents by label/field
+// equality and regexp, to turn a PromQL range vector into a date_histogram
+// bucketed rate, and to turn a LogQL stream selector with its line filter
+// into a single count of matching documents.
+package translator
+
+import "fmt"
+
+// Translation is the result of compiling a query into OpenSearch DSL, plus
+// enough metadata for the scraper to convert the response back into an OTel
+// metric keyed by the original query's label/field set.
+type Translation struct {
+	// Query is the OpenSearch query DSL to send to _search.
+	Query map[string]interface{}
+
+	// Aggs is the aggregation DSL, if any, that must be attached as a
+	// top-level sibling of "query" in the search request body rather than
+	// nested inside Query itself — nesting it there would put "aggs" inside
+	// a query clause once Query is wrapped in the time-range bool filter,
+	// which OpenSearch rejects or silently ignores.
+	Aggs map[string]interface{}
+
+	// MetricName is the base name the translated query's results are
+	// emitted under (e.g. the PromQL metric name, or "logql.count_over_time").
+	MetricName string
+
+	// Labels are the static label/field values extracted from the query
+	// (e.g. PromQL equality matchers), attached to every data point this
+	// translation's results produce.
+	Labels map[string]string
+
+	// Aggregation selects how the scraper reads the response:
+	//   - "count": the query's hits.total.value, recorded as a single gauge.
+	//   - "rate", "count_over_time": AggregationName's date_histogram
+	//     buckets, each recorded as its own gauge keyed by bucket start
+	//     time, using the bucket's native doc_count (divided by
+	//     RangeSeconds for "rate") since arbitrary log documents have no
+	//     generic numeric "counter" field to sub-aggregate.
+	Aggregation     string
+	AggregationName string
+
+	// RangeSeconds is the PromQL range-vector window (e.g. 300 for
+	// rate(foo[5m])), used to convert doc_count into a per-second rate.
+	RangeSeconds float64
+}
+
+// lookupField returns fieldMappings[label] if present, otherwise label
+// itself, so a query against an unmapped field still works rather than
+// failing outright.
+func lookupField(fieldMappings map[string]string, label string) string {
+	if field, ok := fieldMappings[label]; ok {
+		return field
+	}
+	return label
+}
+
+// TranslateLucene wraps text as an OpenSearch query_string query, the
+// simplest of the three supported languages: Lucene query syntax is
+// query_string's native input format.
+func TranslateLucene(text string) (*Translation, error) {
+	if text == "" {
+		return nil, fmt.Errorf("lucene query text must not be empty")
+	}
+
+	return &Translation{
+		Query: map[string]interface{}{
+			"query_string": map[string]interface{}{
+				"query": text,
+			},
+		},
+		MetricName:  "lucene.result_count",
+		Aggregation: "count",
+	}, nil
+}
@@ -0,0 +1,101 @@
+package translator
+
+import "testing"
+
+func TestTranslateLucene(t *testing.T) {
+	translation, err := TranslateLucene(`status:500 AND service:checkout`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Aggregation != "count" {
+		t.Errorf("expected count aggregation, got %q", translation.Aggregation)
+	}
+
+	query, ok := translation.Query["query_string"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a query_string clause, got %v", translation.Query)
+	}
+	if query["query"] != `status:500 AND service:checkout` {
+		t.Errorf("expected query text to be passed through verbatim, got %v", query["query"])
+	}
+}
+
+func TestTranslateLuceneRejectsEmptyQuery(t *testing.T) {
+	if _, err := TranslateLucene(""); err == nil {
+		t.Fatal("expected an error for an empty lucene query")
+	}
+}
+
+func TestTranslatePromQLVectorSelector(t *testing.T) {
+	translation, err := TranslatePromQL(`errors_total{service="checkout"}`, map[string]string{"service": "service.keyword"}, "@timestamp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.MetricName != "errors_total" {
+		t.Errorf("expected metric name errors_total, got %q", translation.MetricName)
+	}
+	if translation.Labels["service"] != "checkout" {
+		t.Errorf("expected service=checkout in extracted labels, got %v", translation.Labels)
+	}
+
+	boolQuery := translation.Query["bool"].(map[string]interface{})
+	must := boolQuery["must"].([]interface{})
+	term := must[0].(map[string]interface{})["term"].(map[string]interface{})
+	if term["service.keyword"] != "checkout" {
+		t.Errorf("expected field mapping to apply, got %v", term)
+	}
+}
+
+func TestTranslatePromQLRate(t *testing.T) {
+	translation, err := TranslatePromQL(`rate(errors_total{service="checkout"}[5m])`, nil, "@timestamp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Aggregation != "rate" {
+		t.Errorf("expected rate aggregation, got %q", translation.Aggregation)
+	}
+	if translation.RangeSeconds != 300 {
+		t.Errorf("expected a 300s range, got %v", translation.RangeSeconds)
+	}
+
+	if _, ok := translation.Query["bool"]; !ok {
+		t.Errorf("expected a bare bool clause, got %v", translation.Query)
+	}
+	if _, ok := translation.Query["aggs"]; ok {
+		t.Errorf("expected aggs to be kept out of Query, not nested inside the query clause, got %v", translation.Query)
+	}
+	if _, ok := translation.Aggs[translation.AggregationName]; !ok {
+		t.Errorf("expected Aggs to contain %q, got %v", translation.AggregationName, translation.Aggs)
+	}
+}
+
+func TestTranslatePromQLRejectsUnsupportedFunction(t *testing.T) {
+	if _, err := TranslatePromQL(`sum(errors_total)`, nil, "@timestamp"); err == nil {
+		t.Fatal("expected an error for an unsupported promql function")
+	}
+}
+
+func TestTranslateLogQL(t *testing.T) {
+	translation, err := TranslateLogQL(`{service="checkout"} |= "timeout"`, map[string]string{"service": "service.keyword"}, "message")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.MetricName != "logql.count_over_time" {
+		t.Errorf("expected metric name logql.count_over_time, got %q", translation.MetricName)
+	}
+	if translation.Labels["service"] != "checkout" {
+		t.Errorf("expected service=checkout in extracted labels, got %v", translation.Labels)
+	}
+
+	boolQuery := translation.Query["bool"].(map[string]interface{})
+	must := boolQuery["must"].([]interface{})
+	if len(must) != 2 {
+		t.Fatalf("expected a label term clause and a line filter clause, got %v", must)
+	}
+}
+
+func TestTranslateLogQLRejectsUnsupportedSyntax(t *testing.T) {
+	if _, err := TranslateLogQL(`{service=~"checkout.*"}`, nil, "message"); err == nil {
+		t.Fatal("expected an error for an unsupported logql label matcher")
+	}
+}